@@ -1,16 +1,21 @@
 package Day
 
 import (
-	"bufio"
-	"fmt"
+	"adventcode2024/input"
+	"adventcode2024/lint"
+	"adventcode2024/registry"
+	"adventcode2024/utils/parse"
+	"context"
+	"io"
 	"math"
-	"os"
 	"sort"
-	"strconv"
-	"strings"
 )
 
-// Day1 solves the Advent of Code 2024 Day 1 puzzle
+func init() {
+	registry.Register(registry.Puzzle{Year: 2024, Day: 1, New: func() registry.Solver { return &day1Solver{} }})
+}
+
+// day1Solver solves the Advent of Code 2024 Day 1 puzzle
 // The puzzle involves processing two lists of numbers and finding relationships between them
 // Part A: For each pair of numbers (one from each list), calculate the absolute difference
 //
@@ -19,16 +24,52 @@ import (
 // Part B: For each number in the left list, count how many times it appears in the right list
 //
 //	and add the product of the number and its count to the total
-func Day1() {
-	fmt.Println("Day 1")
+type day1Solver struct {
+	leftList  []int // Numbers from the left column
+	rightList []int // Numbers from the right column
+}
+
+// Parse reads the two lists of numbers shared by both parts
+func (d *day1Solver) Parse(r io.Reader) error {
+	leftList, rightList, err := GetInputs(r)
+	if err != nil {
+		return err
+	}
+	d.leftList = leftList
+	d.rightList = rightList
+	return nil
+}
 
-	// Solve part A and print result
-	solutionA := solutionA()
-	fmt.Println("Solution A:", solutionA)
+// Lint checks that every line holds two location IDs of at least 0
+func (d *day1Solver) Lint(r io.Reader) (lint.Problems, error) {
+	lines, err := parse.Lines(r)
+	if err != nil {
+		return nil, err
+	}
+	const expected = "two numbers separated by spaces"
+	var problems lint.Problems
+	lint.NotEmpty(&problems, lines, expected)
+	for _, l := range lines {
+		fields := l.Fields()
+		if len(fields) != 2 {
+			problems.Add(l.Number, l.Field, expected)
+			continue
+		}
+		for _, f := range fields {
+			lint.Int(&problems, l.Number, f, 0, math.MaxInt)
+		}
+	}
+	return problems, nil
+}
+
+// Part1 returns the sum of the distances between the sorted lists
+func (d *day1Solver) Part1(ctx context.Context) (int64, error) {
+	return int64(solutionA(d.leftList, d.rightList)), nil
+}
 
-	// Solve part B and print result
-	solutionB := solutionB()
-	fmt.Println("Solution B:", solutionB)
+// Part2 returns the similarity score of the two lists
+func (d *day1Solver) Part2(ctx context.Context) (int64, error) {
+	return int64(solutionB(d.leftList, d.rightList)), nil
 }
 
 // solutionA solves part A of the puzzle
@@ -36,14 +77,7 @@ func Day1() {
 // and sums all differences
 // Returns:
 //   - The sum of all absolute differences between paired numbers
-//   - -1 if there was an error reading the input
-func solutionA() int {
-	// Get input data
-	leftList, rightList, shouldReturn := GetInputs()
-	if shouldReturn {
-		return -1
-	}
-
+func solutionA(leftList, rightList []int) int {
 	// Sort both lists to ensure proper pairing
 	sort.Ints(leftList)
 	sort.Ints(rightList)
@@ -59,59 +93,33 @@ func solutionA() int {
 	return solution
 }
 
-// GetInputs reads the input file and parses it into two lists of integers
-// The input file contains pairs of numbers separated by three spaces
+// GetInputs reads the input and parses it into two lists of integers
+// The input contains pairs of numbers separated by spaces
 // Returns:
 //   - left: List of numbers from the left column
 //   - right: List of numbers from the right column
-//   - shouldReturn: true if there was an error reading the file
-func GetInputs() ([]int, []int, bool) {
+//   - err: Any error reading the input, or a *input.ParseError for a malformed line
+func GetInputs(r io.Reader) ([]int, []int, error) {
 	var left []int
 	var right []int
 
-	// Open file and gather raw inputs
-	var inputFileNameBegins = "input" // "input" or "test"
-	file, err := os.Open(fmt.Sprintf("../inputs/%s1.txt", inputFileNameBegins))
-	if err != nil {
-		fmt.Println(err)
-		return nil, nil, true
-	}
-	defer file.Close()
-
-	// Read all lines into memory
-	var inputMemory string
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		inputMemory += scanner.Text() + "\n"
-	}
-	// remove last \n
-	inputMemory = inputMemory[:len(inputMemory)-1]
-
-	if err := scanner.Err(); err != nil {
-		fmt.Println(err)
-		return nil, nil, true
-	}
-
-	// split inputMemory into lines
-	lines := strings.Split(inputMemory, "\n")
-
 	// Parse each line into two numbers
-	for _, line := range lines {
-		slice := strings.Split(line, "   ") // Split by three spaces
-
-		// Parse left number
-		t, err := strconv.Atoi(slice[0])
-		if err == nil {
-			left = append(left, t)
+	err := parse.Each(r, func(l parse.Line) error {
+		pair, err := parse.IntList(l.Field, "")
+		if err != nil {
+			return input.WithLine(err, l.Number)
 		}
-
-		// Parse right number
-		t, err = strconv.Atoi(slice[1])
-		if err == nil {
-			right = append(right, t)
+		if len(pair) != 2 {
+			return l.Error("two numbers separated by spaces")
 		}
+		left = append(left, pair[0])
+		right = append(right, pair[1])
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
 	}
-	return left, right, false
+	return left, right, nil
 }
 
 // solutionB solves part B of the puzzle
@@ -119,14 +127,7 @@ func GetInputs() ([]int, []int, bool) {
 // and adds the product of the number and its count to the total
 // Returns:
 //   - The sum of all products (number × count)
-//   - -1 if there was an error reading the input
-func solutionB() int {
-	// Get input data
-	leftList, rightList, shouldReturn := GetInputs()
-	if shouldReturn {
-		return -1
-	}
-
+func solutionB(leftList, rightList []int) int {
 	// Calculate sum of products
 	var solution int = 0
 	for _, leftItem := range leftList {
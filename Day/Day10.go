@@ -2,110 +2,87 @@
 package Day
 
 import (
-	"bufio"
-	"fmt"
-	"os"
+	"adventcode2024/lint"
+	"adventcode2024/logging"
+	"adventcode2024/registry"
+	"adventcode2024/utils/grid"
+	"adventcode2024/utils/parse"
+	"context"
+	"io"
 	"strconv"
 )
 
-// Day10Position represents a coordinate position in the game map.
-// It stores both row and column indices.
-type Day10Position struct {
-	row int
-	col int
+func init() {
+	registry.Register(registry.Puzzle{Year: 2024, Day: 10, New: func() registry.Solver { return &day10Solver{} }})
 }
 
 // Day10Trail represents a complete trail from a starting position (head)
 // to an ending position (tail). Each trail must start at elevation 0
 // and end at elevation 9.
 type Day10Trail struct {
-	headRow, headCol int // Starting position coordinates
-	tailRow, tailCol int // Ending position coordinates
+	head grid.Point // Starting position
+	tail grid.Point // Ending position
 }
 
-// Day10 solves the Day 10 puzzle of Advent of Code 2024.
+// day10Solver solves the Day 10 puzzle of Advent of Code 2024.
 // The puzzle involves finding valid trails in a map where:
 // - Each position has an elevation from 0-9
 // - Trails must start at elevation 0
 // - Trails must end at elevation 9
 // - Each step must increase elevation by exactly 1
-func Day10() {
-	fmt.Println("2024 Day 10 start")
-
-	// Read input file
-	file, err := os.Open("./inputs/input10.txt")
-	if err != nil {
-		fmt.Println("Error opening file:", err)
-		return
-	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	var inputLines []string
-	for scanner.Scan() {
-		inputLines = append(inputLines, scanner.Text())
-	}
+type day10Solver struct {
+	registry.Logs
+	gameMap    *grid.Grid[int] // Elevation of every position
+	trailHeads []grid.Point    // Positions with elevation 0
+}
 
-	if err := scanner.Err(); err != nil {
-		fmt.Println("Error reading file:", err)
-		return
+// day10Elevation is the grid mapper for the topographic map.
+// Impassable positions are drawn as '.' in some examples, they get
+// an elevation no trail can step onto.
+func day10Elevation(r rune) (int, bool) {
+	switch {
+	case r == '.':
+		return -1, true
+	case r >= '0' && r <= '9':
+		return int(r - '0'), true
 	}
+	return 0, false
+}
 
-	// Build map of inputs
-	rows := len(inputLines)
-	if rows == 0 {
-		return
-	}
-	cols := len(inputLines[0])
-
-	// Create 2D slice for the map
-	gameMap := make([][]int, rows)
-	for i := range gameMap {
-		gameMap[i] = make([]int, cols)
-		for j, ch := range inputLines[i] {
-			num, _ := strconv.Atoi(string(ch))
-			gameMap[i][j] = num
-		}
+// Parse reads the topographic map and finds the trail heads
+func (d *day10Solver) Parse(r io.Reader) error {
+	gameMap, err := grid.Read(r, day10Elevation, "an elevation digit or '.'")
+	if err != nil {
+		return err
 	}
 
-	// Print map
-	fmt.Printf("map size: %dx%d\n", rows, cols)
-	// for _, row := range gameMap {
-	// 	for _, cell := range row {
-	// 		fmt.Print(cell)
-	// 	}
-	// 	fmt.Println()
-	// }
+	d.Log().Debug("map", "rows", gameMap.Rows(), "cols", gameMap.Cols(), "gameMap", logging.Lazy(func() any {
+		return gameMap.Render(func(_ grid.Point, elevation int) string { return strconv.Itoa(elevation) })
+	}))
 
 	// Find all trail heads (cells with value 0)
-	var trailHeads []Day10Position
-	for i := 0; i < rows; i++ {
-		for j := 0; j < cols; j++ {
-			if gameMap[i][j] == 0 {
-				trailHeads = append(trailHeads, Day10Position{row: i, col: j})
-			}
-		}
-	}
-
-	fmt.Printf("trailHeads size: %d\n", len(trailHeads))
-	// for _, head := range trailHeads {
-	// 	fmt.Printf("%d, %d\n", head.row, head.col)
-	// }
+	trailHeads := gameMap.FindAll(func(elevation int) bool { return elevation == 0 })
+	d.Log().Debug("trail heads", "count", len(trailHeads), "trailHeads", trailHeads)
 
-	// Find all trails
-	var trails []Day10Trail
+	d.gameMap = gameMap
+	d.trailHeads = trailHeads
+	return nil
+}
 
-	for _, head := range trailHeads {
-		if day10TakeNextStep(head, gameMap, head, &trails) {
-			fmt.Printf("trail found for trailHead: %d,%d\n", head.row, head.col)
-		}
+// Lint checks that the map is a rectangle of elevation digits and '.'
+func (d *day10Solver) Lint(r io.Reader) (lint.Problems, error) {
+	lines, err := parse.Lines(r)
+	if err != nil {
+		return nil, err
 	}
+	var problems lint.Problems
+	lint.Grid(&problems, lines, lint.Accepts(day10Elevation), "an elevation digit or '.'")
+	return problems, nil
+}
 
-	// Print trails
-	// fmt.Printf("trails size: %d\n", len(trails))
-	// for _, trail := range trails {
-	// 	fmt.Printf("head %d,%d tail %d,%d\n", trail.headRow, trail.headCol, trail.tailRow, trail.tailCol)
-	// }
+// Part1 returns the sum of the trail head scores, the number of distinct 9s each head reaches
+func (d *day10Solver) Part1(ctx context.Context) (int64, error) {
+	trails := d.findTrails()
 
 	// Remove duplicate trails
 	var destTrails []Day10Trail
@@ -122,64 +99,63 @@ func Day10() {
 		}
 	}
 
-	// Print destTrails
-	// fmt.Printf("destTrails size: %d\n", len(destTrails))
-	// for _, trail := range destTrails {
-	// 	fmt.Printf("head %d,%d tail %d,%d\n", trail.headRow, trail.headCol, trail.tailRow, trail.tailCol)
-	// }
+	d.Log().Debug("distinct trails", "count", len(destTrails))
 
-	// Calculate and print scores
-	totalScore := 0
-	for _, head := range trailHeads {
-		trailScore := 0
-		for _, trail := range destTrails {
-			if trail.headRow == head.row && trail.headCol == head.col {
-				trailScore++
-			}
+	return int64(d.totalScore(destTrails)), nil
+}
+
+// Part2 returns the sum of the trail head ratings, the number of distinct trails from each head
+func (d *day10Solver) Part2(ctx context.Context) (int64, error) {
+	return int64(d.totalScore(d.findTrails())), nil
+}
+
+// findTrails walks every trail from every trail head
+// A head reaching the same 9 along different paths yields one trail per path
+func (d *day10Solver) findTrails() []Day10Trail {
+	var trails []Day10Trail
+
+	for _, head := range d.trailHeads {
+		if day10TakeNextStep(head, d.gameMap, head, &trails) {
+			d.Log().Debug("trail found", "trailHead", head)
 		}
-		//fmt.Printf("trailHead %d,%d score %d\n", head.row, head.col, trailScore)
-		totalScore += trailScore
 	}
-	fmt.Printf("Part 1 totalScore: %d\n", totalScore)
+	d.Log().Debug("trails", "count", len(trails))
 
-	// Calculate Part 2 score
-	totalScore = 0
-	for _, head := range trailHeads {
+	return trails
+}
+
+// totalScore counts the trails starting at each trail head and sums the counts
+func (d *day10Solver) totalScore(trails []Day10Trail) int {
+	totalScore := 0
+	for _, head := range d.trailHeads {
 		trailScore := 0
 		for _, trail := range trails {
-			if trail.headRow == head.row && trail.headCol == head.col {
+			if trail.head == head {
 				trailScore++
 			}
 		}
-		//fmt.Printf("trailHead %d,%d score %d\n", head.row, head.col, trailScore)
+		d.Log().Debug("trail head score", "trailHead", head, "score", trailScore)
 		totalScore += trailScore
 	}
-	fmt.Printf("Part 2 totalScore: %d\n", totalScore)
-
-	fmt.Println("2024 Day 10 end")
+	return totalScore
 }
 
 // day10TakeNextStep recursively explores possible paths from the current position.
 // It returns true if a valid trail ending at elevation 9 is found.
 // Parameters:
 //   - currentPos: The current position being explored
-//   - gameMap: The 2D grid containing elevation values
+//   - gameMap: The grid containing elevation values
 //   - startingPos: The original starting position (elevation 0)
 //   - trails: Pointer to slice storing all valid trails found
-func day10TakeNextStep(currentPos Day10Position, gameMap [][]int, startingPos Day10Position, trails *[]Day10Trail) bool {
+func day10TakeNextStep(currentPos grid.Point, gameMap *grid.Grid[int], startingPos grid.Point, trails *[]Day10Trail) bool {
 	foundTrail := false
-	currentElevation := gameMap[currentPos.row][currentPos.col]
+	currentElevation := gameMap.At(currentPos)
 
-	for _, step := range day10GetPossibleSteps(currentPos, gameMap) {
-		possibleElevation := gameMap[step.row][step.col]
+	for step := range gameMap.Neighbours4(currentPos) {
+		possibleElevation := gameMap.At(step)
 		if possibleElevation == currentElevation+1 {
 			if possibleElevation == 9 {
-				*trails = append(*trails, Day10Trail{
-					headRow: startingPos.row,
-					headCol: startingPos.col,
-					tailRow: step.row,
-					tailCol: step.col,
-				})
+				*trails = append(*trails, Day10Trail{head: startingPos, tail: step})
 				foundTrail = true
 			} else {
 				foundTrail = day10TakeNextStep(step, gameMap, startingPos, trails)
@@ -188,31 +164,3 @@ func day10TakeNextStep(currentPos Day10Position, gameMap [][]int, startingPos Da
 	}
 	return foundTrail
 }
-
-// day10GetPossibleSteps returns all valid adjacent positions from the current position.
-// A valid position must be within the game map boundaries.
-// Parameters:
-//   - currentPos: The position to check adjacent squares from
-//   - gameMap: The 2D grid containing elevation values
-//
-// Returns:
-//   - A slice of valid Day10Position that can be moved to
-func day10GetPossibleSteps(currentPos Day10Position, gameMap [][]int) []Day10Position {
-	possibleSteps := []Day10Position{
-		{row: currentPos.row - 1, col: currentPos.col}, // up
-		{row: currentPos.row + 1, col: currentPos.col}, // down
-		{row: currentPos.row, col: currentPos.col - 1}, // left
-		{row: currentPos.row, col: currentPos.col + 1}, // right
-	}
-
-	var validSteps []Day10Position
-	rows, cols := len(gameMap), len(gameMap[0])
-
-	for _, step := range possibleSteps {
-		if step.row >= 0 && step.row < rows && step.col >= 0 && step.col < cols {
-			validSteps = append(validSteps, step)
-		}
-	}
-
-	return validSteps
-}
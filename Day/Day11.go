@@ -2,92 +2,132 @@
 package Day
 
 import (
-	"bufio"
-	"fmt"
-	"os"
+	"adventcode2024/gen"
+	"adventcode2024/input"
+	"adventcode2024/lint"
+	"adventcode2024/registry"
+	"adventcode2024/utils/parse"
+	"context"
+	"io"
+	"math"
+	"math/rand/v2"
 	"strconv"
 	"strings"
 )
 
+func init() {
+	registry.Register(registry.Puzzle{Year: 2024, Day: 11, New: func() registry.Solver { return &day11Solver{} }})
+}
+
 // blinkCache stores the cached results of blink operations
 type blinkCache struct {
 	stone      int64
 	blinkCount int
 }
 
-// Global cache map to store blink results
-var cachedBlinks = make(map[blinkCache]int64)
-
-// Day11 solves the Day 11 puzzle of Advent of Code 2024.
+// day11Solver solves the Day 11 puzzle of Advent of Code 2024.
 // The puzzle involves "blinking" stones according to specific rules:
 // - Rule 1: flip 0 to 1
 // - Rule 2: even length numbers are split into halves
 // - Rule 3: odd length numbers are multiplied by 2024
-func Day11() {
-	fmt.Println("2024 Day 11 start")
+//
+// Part 1 counts the stones after 25 blinks, part 2 after 75 blinks
+type day11Solver struct {
+	registry.Logs
+	stones       []int64              // Engravings on the starting stones
+	cachedBlinks map[blinkCache]int64 // Stone counts by stone and blinks left, shared by both parts
+}
 
-	// Read input file
-	file, err := os.Open("../inputs/input11.txt")
+// Parse reads the space separated starting stones
+func (d *day11Solver) Parse(r io.Reader) error {
+	// Parse every line into array of int64
+	var stones []int64
+	err := parse.Each(r, func(l parse.Line) error {
+		numbers, err := parse.Int64List(l.Field, "")
+		if err != nil {
+			return input.WithLine(err, l.Number)
+		}
+		stones = append(stones, numbers...)
+		return nil
+	})
 	if err != nil {
-		fmt.Println("Error opening file:", err)
-		return
+		return err
 	}
-	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
-	var inputMemory strings.Builder
-	for scanner.Scan() {
-		inputMemory.WriteString(scanner.Text())
-	}
+	d.Log().Debug("starting stones", "stones", stones)
 
-	if err := scanner.Err(); err != nil {
-		fmt.Println("Error reading file:", err)
-		return
-	}
+	d.stones = stones
+	d.cachedBlinks = make(map[blinkCache]int64)
+	return nil
+}
 
-	fmt.Printf("inputMemory size: %d\n", inputMemory.Len())
+// Lint checks that the stones are a single line of numbers of 0 or more
+func (d *day11Solver) Lint(r io.Reader) (lint.Problems, error) {
+	lines, err := parse.Lines(r)
+	if err != nil {
+		return nil, err
+	}
+	var problems lint.Problems
+	line, ok := lint.SingleLine(&problems, lines, "stones separated by spaces")
+	if !ok {
+		return problems, nil
+	}
+	for _, f := range line.Fields() {
+		lint.Int(&problems, line.Number, f, 0, math.MaxInt)
+	}
+	return problems, nil
+}
 
-	// Parse input string into array of int64
-	var stones []int64
-	for _, numStr := range strings.Split(inputMemory.String(), " ") {
-		num, err := strconv.ParseInt(numStr, 10, 64)
-		if err != nil {
-			fmt.Printf("Error parsing number %s: %v\n", numStr, err)
-			continue
+// Generate writes a line of Size stones, 8 by default like the real input.
+// Density is the share of stones with a single digit, 0.25 by default, the
+// others having 2 to 7 digits.
+func (d *day11Solver) Generate(w io.Writer, rng *rand.Rand, opts gen.Options) error {
+	opts = opts.Defaults(8, 0.25)
+	stones := make([]string, opts.Size)
+	for i := range stones {
+		if gen.Chance(rng, opts.Density) {
+			stones[i] = strconv.Itoa(rng.IntN(10))
+		} else {
+			stones[i] = strconv.Itoa(gen.Between(rng, 10, 9_999_999))
 		}
-		stones = append(stones, num)
 	}
+	_, err := io.WriteString(w, strings.Join(stones, " ")+"\n")
+	return err
+}
 
-	// Print starting stones
-	fmt.Print("starting stones: ")
-	for _, stone := range stones {
-		fmt.Printf("%d ", stone)
-	}
-	fmt.Println()
+// Part1 returns the number of stones after 25 blinks
+func (d *day11Solver) Part1(ctx context.Context) (int64, error) {
+	return d.blinkStones(25), nil
+}
 
-	blinkCount := 75
+// Part2 returns the number of stones after 75 blinks
+func (d *day11Solver) Part2(ctx context.Context) (int64, error) {
+	return d.blinkStones(75), nil
+}
+
+// blinkStones returns the total number of stones after blinking blinkCount times
+func (d *day11Solver) blinkStones(blinkCount int) int64 {
 	var totalStoneCount int64 = 0
 
-	fmt.Printf("Blinking %d times, final row\n", blinkCount)
-	for _, stone := range stones {
-		blinkRecurseCount := blinkRecurse(stone, blinkCount)
+	for _, stone := range d.stones {
+		blinkRecurseCount := d.blinkRecurse(stone, blinkCount)
 		totalStoneCount += blinkRecurseCount
-		fmt.Printf("blinkRecurseCount: %d stoneCount: %d\n", blinkRecurseCount, totalStoneCount)
+		d.Log().Debug("blinked stone", "stone", stone, "blinkRecurseCount", blinkRecurseCount, "stoneCount", totalStoneCount)
 	}
-
-	fmt.Printf("\nstoneCount: %d\n", totalStoneCount)
-	fmt.Println("2024 Day 11 end")
+	d.Log().Info("blinked", "blinkCount", blinkCount, "stoneCount", totalStoneCount, "cached", len(d.cachedBlinks))
+	return totalStoneCount
 }
 
 // blinkRecurse implements the recursive blinking logic
-func blinkRecurse(stone int64, blinkCount int) int64 {
+// Counts are cached on the solver, so part 2 reuses the counts of part 1
+func (d *day11Solver) blinkRecurse(stone int64, blinkCount int) int64 {
 	if blinkCount <= 0 {
 		return 1
 	}
 
 	// Check cache
 	cacheKey := blinkCache{stone: stone, blinkCount: blinkCount}
-	if cachedResult, exists := cachedBlinks[cacheKey]; exists {
+	if cachedResult, exists := d.cachedBlinks[cacheKey]; exists {
 		return cachedResult
 	}
 
@@ -96,7 +136,7 @@ func blinkRecurse(stone int64, blinkCount int) int64 {
 	// Implement the three rules
 	if stone == 0 {
 		// Rule 1: flip 0 to 1
-		stoneCount = blinkRecurse(1, blinkCount-1)
+		stoneCount = d.blinkRecurse(1, blinkCount-1)
 	} else {
 		// Convert stone to string to check length
 		stoneStr := strconv.FormatInt(stone, 10)
@@ -105,14 +145,14 @@ func blinkRecurse(stone int64, blinkCount int) int64 {
 			mid := len(stoneStr) / 2
 			leftStone, _ := strconv.ParseInt(stoneStr[:mid], 10, 64)
 			rightStone, _ := strconv.ParseInt(stoneStr[mid:], 10, 64)
-			stoneCount = blinkRecurse(leftStone, blinkCount-1) + blinkRecurse(rightStone, blinkCount-1)
+			stoneCount = d.blinkRecurse(leftStone, blinkCount-1) + d.blinkRecurse(rightStone, blinkCount-1)
 		} else {
 			// Rule 3: multiply odd length numbers by 2024
-			stoneCount = blinkRecurse(stone*2024, blinkCount-1)
+			stoneCount = d.blinkRecurse(stone*2024, blinkCount-1)
 		}
 	}
 
 	// Update cache
-	cachedBlinks[cacheKey] = stoneCount
+	d.cachedBlinks[cacheKey] = stoneCount
 	return stoneCount
 }
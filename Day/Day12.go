@@ -2,16 +2,29 @@
 package Day
 
 import (
-	"bufio"
-	"fmt"
-	"os"
-	"strings"
+	"adventcode2024/gen"
+	"adventcode2024/lint"
+	"adventcode2024/logging"
+	"adventcode2024/registry"
+	"adventcode2024/render"
+	"adventcode2024/utils/grid"
+	"adventcode2024/utils/parse"
+	"context"
+	"image"
+	"image/color"
+	"io"
+	"math/rand/v2"
+	"unicode"
 )
 
+func init() {
+	registry.Register(registry.Puzzle{Year: 2024, Day: 12, New: func() registry.Solver { return &day12Solver{} }})
+}
+
 // Plot represents a single plot in the garden
 type day12Plot struct {
 	plant    string
-	x, y     int
+	pos      grid.Point
 	regionID int
 }
 
@@ -22,95 +35,136 @@ type day12Region struct {
 	perimeter int
 }
 
-// Day12 solves the Day 12 puzzle of Advent of Code 2024
-func Day12() {
-	fmt.Println("2024 Day 12 start")
+// day12Solver solves the Day 12 puzzle of Advent of Code 2024
+// Only part 1, pricing fences by area times perimeter, is solved
+type day12Solver struct {
+	registry.Logs
+	plots *grid.Grid[*day12Plot] // Garden plots
+}
 
-	// Read input file
-	file, err := os.Open("../inputs/input12.txt")
+// Parse reads the garden map into plots
+func (d *day12Solver) Parse(r io.Reader) error {
+	newPlot := func(r rune) (*day12Plot, bool) {
+		return &day12Plot{plant: string(r), regionID: -1}, unicode.IsUpper(r)
+	}
+	plots, err := grid.Read(r, newPlot, "an uppercase plant letter")
 	if err != nil {
-		fmt.Println("Error opening file:", err)
-		return
+		return err
 	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	var inputMemory strings.Builder
-	for scanner.Scan() {
-		inputMemory.WriteString(scanner.Text() + "\n")
+	for pos, plot := range plots.All() {
+		plot.pos = pos
 	}
 
-	if err := scanner.Err(); err != nil {
-		fmt.Println("Error reading file:", err)
-		return
+	d.Log().Debug("garden", "rows", plots.Rows(), "cols", plots.Cols(), "plots", logging.Lazy(func() any {
+		return plots.Render(func(_ grid.Point, plot *day12Plot) string { return plot.plant })
+	}))
+
+	d.plots = plots
+	return nil
+}
+
+// Lint checks that the garden is a rectangle of uppercase plant letters
+func (d *day12Solver) Lint(r io.Reader) (lint.Problems, error) {
+	lines, err := parse.Lines(r)
+	if err != nil {
+		return nil, err
 	}
+	var problems lint.Problems
+	lint.Grid(&problems, lines, unicode.IsUpper, "an uppercase plant letter")
+	return problems, nil
+}
 
-	fmt.Printf("inputMemory size: %d\n", inputMemory.Len())
-
-	// Parse input into plots
-	lines := strings.Split(strings.TrimSpace(inputMemory.String()), "\n")
-	plots := make([][]*day12Plot, len(lines))
-	for i, line := range lines {
-		plots[i] = make([]*day12Plot, len(line))
-		for j, char := range line {
-			plots[i][j] = &day12Plot{
-				plant:    string(char),
-				x:        i,
-				y:        j,
-				regionID: -1,
+// Generate writes a square garden, Size plots wide and 140 by default like the
+// real input. Density is the chance that a plot grows a random plant instead
+// of the plant of the plot above or to its left, 0.05 by default, so a lower
+// density gives fewer and larger regions.
+func (d *day12Solver) Generate(w io.Writer, rng *rand.Rand, opts gen.Options) error {
+	opts = opts.Defaults(140, 0.05)
+	n := opts.Size
+	above := make([]byte, n)
+	row := make([]byte, n+1)
+	row[n] = '\n'
+	for r := range n {
+		for c := range n {
+			switch {
+			case r == 0 && c == 0 || gen.Chance(rng, opts.Density):
+				row[c] = byte('A' + rng.IntN(26))
+			case r == 0 || c > 0 && rng.IntN(2) == 0:
+				row[c] = row[c-1]
+			default:
+				row[c] = above[c]
 			}
 		}
-	}
-
-	// Print plot
-	fmt.Printf("plot: %dx%d\n", len(plots), len(plots[0]))
-	for _, row := range plots {
-		for _, cell := range row {
-			fmt.Print(cell.plant)
+		if _, err := w.Write(row); err != nil {
+			return err
 		}
-		fmt.Println()
+		copy(above, row)
 	}
+	return nil
+}
+
+// Part1 returns the total price of fencing every region, area times perimeter
+func (d *day12Solver) Part1(ctx context.Context) (int64, error) {
+	plots := d.plots
 
 	// Get unique plants
 	plants := getPlants(plots)
-	fmt.Printf("plants: %d\n", len(plants))
-	for _, plant := range plants {
-		fmt.Println(plant)
-	}
+	d.Log().Debug("plants", "count", len(plants), "plants", plants)
 
 	// Get regions from plots
-	regions := getRegionsFromPlots(plots)
+	regions := d.assignRegions()
 
 	// Calculate area and perimeter for each region
 	var totalPrice int64
 	for _, region := range regions {
-		region.perimeter = calcPerimeter(region, len(plots), len(plots[0]))
+		region.perimeter = calcPerimeter(region, plots)
 		price := int64(len(region.plots)) * int64(region.perimeter)
 		totalPrice += price
 	}
 
-	// Print regions
-	fmt.Printf("regions: %d\n", len(regions))
+	d.Log().Info("regions", "count", len(regions))
 	for _, region := range regions {
-		fmt.Printf("Region %d area x peri %d x %d plots(%d):",
-			region.id, len(region.plots), region.perimeter, len(region.plots))
-		for _, plot := range region.plots {
-			fmt.Printf("  %d,%d", plot.x, plot.y)
-		}
-		fmt.Println()
+		d.Log().Debug("region", "id", region.id, "area", len(region.plots), "perimeter", region.perimeter,
+			"plots", logging.Lazy(func() any {
+				positions := make([]grid.Point, len(region.plots))
+				for i, plot := range region.plots {
+					positions[i] = plot.pos
+				}
+				return positions
+			}))
 	}
 
-	fmt.Printf("total price: %d\n", totalPrice)
-	fmt.Println("2024 Day 12 end")
+	return totalPrice, nil
+}
+
+// Part2 is not solved yet
+func (d *day12Solver) Part2(ctx context.Context) (int64, error) {
+	return 0, registry.ErrNotImplemented
+}
+
+// Draw pictures the garden with every region in its own colour,
+// so regions of the same plant that do not touch are told apart
+func (d *day12Solver) Draw(ctx context.Context, scale int) (image.Image, error) {
+	d.assignRegions()
+	return render.Image(d.plots, func(_ grid.Point, plot *day12Plot) color.Color {
+		return render.Distinct(plot.regionID)
+	}, scale), nil
+}
+
+// assignRegions groups the plots into regions from scratch
+// Regions are assigned while walking the plots, so every plot starts unassigned
+func (d *day12Solver) assignRegions() map[int]*day12Region {
+	for _, plot := range d.plots.All() {
+		plot.regionID = -1
+	}
+	return getRegionsFromPlots(d.plots)
 }
 
 // getPlants returns a slice of unique plant types
-func getPlants(plots [][]*day12Plot) []string {
+func getPlants(plots *grid.Grid[*day12Plot]) []string {
 	plantsMap := make(map[string]bool)
-	for _, row := range plots {
-		for _, plot := range row {
-			plantsMap[plot.plant] = true
-		}
+	for _, plot := range plots.All() {
+		plantsMap[plot.plant] = true
 	}
 
 	plants := make([]string, 0, len(plantsMap))
@@ -121,72 +175,51 @@ func getPlants(plots [][]*day12Plot) []string {
 }
 
 // getRegionsFromPlots identifies and returns connected regions of the same plant type
-func getRegionsFromPlots(plots [][]*day12Plot) map[int]*day12Region {
+func getRegionsFromPlots(plots *grid.Grid[*day12Plot]) map[int]*day12Region {
 	regions := make(map[int]*day12Region)
 	regionID := -1
 
-	for i := range plots {
-		for j := range plots[i] {
-			plot := plots[i][j]
+	for pos, plot := range plots.All() {
+		updatedRegion := -1
 
-			// Get valid neighbor coordinates
-			neighbors := [][2]int{
-				{i - 1, j}, // top
-				{i, j + 1}, // right
-				{i + 1, j}, // bottom
-				{i, j - 1}, // left
-			}
-
-			// Filter valid neighbors
-			validNeighbors := make([][2]int, 0)
-			for _, addr := range neighbors {
-				if addr[0] >= 0 && addr[0] < len(plots) &&
-					addr[1] >= 0 && addr[1] < len(plots[0]) {
-					validNeighbors = append(validNeighbors, addr)
+		// Check if plot is regionless
+		if plot.regionID == -1 {
+			// Try to join existing region
+			for addr := range plots.Neighbours4(pos) {
+				neighbor := plots.At(addr)
+				if neighbor.plant == plot.plant && neighbor.regionID != -1 {
+					plot.regionID = neighbor.regionID
+					regions[neighbor.regionID].plots = append(regions[neighbor.regionID].plots, plot)
+					updatedRegion = neighbor.regionID
+					break
 				}
 			}
 
-			updatedRegion := -1
-
-			// Check if plot is regionless
+			// Create new region if still regionless
 			if plot.regionID == -1 {
-				// Try to join existing region
-				for _, addr := range validNeighbors {
-					neighbor := plots[addr[0]][addr[1]]
-					if neighbor.plant == plot.plant && neighbor.regionID != -1 {
-						plot.regionID = neighbor.regionID
-						regions[neighbor.regionID].plots = append(regions[neighbor.regionID].plots, plot)
-						updatedRegion = neighbor.regionID
-						break
-					}
-				}
-
-				// Create new region if still regionless
-				if plot.regionID == -1 {
-					regionID++
-					plot.regionID = regionID
-					newRegion := &day12Region{
-						id:    regionID,
-						plots: []*day12Plot{plot},
-					}
-					regions[regionID] = newRegion
-					updatedRegion = regionID
+				regionID++
+				plot.regionID = regionID
+				newRegion := &day12Region{
+					id:    regionID,
+					plots: []*day12Plot{plot},
 				}
+				regions[regionID] = newRegion
+				updatedRegion = regionID
 			}
+		}
 
-			// Spread to neighbors
-			for _, addr := range validNeighbors {
-				neighbor := plots[addr[0]][addr[1]]
-				if neighbor.plant == plot.plant && neighbor.regionID == -1 {
-					neighbor.regionID = plot.regionID
-					regions[plot.regionID].plots = append(regions[plot.regionID].plots, neighbor)
-					updatedRegion = plot.regionID
-				}
+		// Spread to neighbors
+		for addr := range plots.Neighbours4(pos) {
+			neighbor := plots.At(addr)
+			if neighbor.plant == plot.plant && neighbor.regionID == -1 {
+				neighbor.regionID = plot.regionID
+				regions[plot.regionID].plots = append(regions[plot.regionID].plots, neighbor)
+				updatedRegion = plot.regionID
 			}
+		}
 
-			if updatedRegion != -1 {
-				regionSpread(regions, plots, updatedRegion)
-			}
+		if updatedRegion != -1 {
+			regionSpread(regions, plots, updatedRegion)
 		}
 	}
 
@@ -194,28 +227,18 @@ func getRegionsFromPlots(plots [][]*day12Plot) map[int]*day12Region {
 }
 
 // regionSpread recursively spreads a region to connected plots of the same type
-func regionSpread(regions map[int]*day12Region, plots [][]*day12Plot, regionID int) {
+func regionSpread(regions map[int]*day12Region, plots *grid.Grid[*day12Plot], regionID int) {
 	region := regions[regionID]
 	for spreading := true; spreading; {
 		spreading = false
 		for _, plot := range region.plots {
-			neighbors := [][2]int{
-				{plot.x - 1, plot.y}, // top
-				{plot.x, plot.y + 1}, // right
-				{plot.x + 1, plot.y}, // bottom
-				{plot.x, plot.y - 1}, // left
-			}
-
-			for _, addr := range neighbors {
-				if addr[0] >= 0 && addr[0] < len(plots) &&
-					addr[1] >= 0 && addr[1] < len(plots[0]) {
-					neighbor := plots[addr[0]][addr[1]]
-					if neighbor.plant == plot.plant && neighbor.regionID == -1 {
-						neighbor.regionID = regionID
-						region.plots = append(region.plots, neighbor)
-						spreading = true
-						regionSpread(regions, plots, regionID)
-					}
+			for addr := range plots.Neighbours4(plot.pos) {
+				neighbor := plots.At(addr)
+				if neighbor.plant == plot.plant && neighbor.regionID == -1 {
+					neighbor.regionID = regionID
+					region.plots = append(region.plots, neighbor)
+					spreading = true
+					regionSpread(regions, plots, regionID)
 				}
 			}
 			if spreading {
@@ -226,26 +249,16 @@ func regionSpread(regions map[int]*day12Region, plots [][]*day12Plot, regionID i
 }
 
 // calcPerimeter calculates the perimeter of a region
-func calcPerimeter(region *day12Region, matrixRows, matrixCols int) int {
+func calcPerimeter(region *day12Region, plots *grid.Grid[*day12Plot]) int {
 	perimeter := 0
 	for _, plot := range region.plots {
-		neighbors := [][2]int{
-			{plot.x - 1, plot.y}, // top
-			{plot.x, plot.y + 1}, // right
-			{plot.x + 1, plot.y}, // bottom
-			{plot.x, plot.y - 1}, // left
-		}
-
 		neighborCount := 0
-		for _, addr := range neighbors {
-			if addr[0] >= 0 && addr[0] < matrixRows &&
-				addr[1] >= 0 && addr[1] < matrixCols {
-				// Check if neighbor is in region
-				for _, neighborPlot := range region.plots {
-					if neighborPlot.x == addr[0] && neighborPlot.y == addr[1] {
-						neighborCount++
-						break
-					}
+		for addr := range plots.Neighbours4(plot.pos) {
+			// Check if neighbor is in region
+			for _, neighborPlot := range region.plots {
+				if neighborPlot.pos == addr {
+					neighborCount++
+					break
 				}
 			}
 		}
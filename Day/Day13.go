@@ -2,13 +2,24 @@
 package Day
 
 import (
-	"bufio"
+	"adventcode2024/gen"
+	"adventcode2024/input"
+	"adventcode2024/lint"
+	"adventcode2024/registry"
+	"adventcode2024/utils/parse"
+	"context"
 	"fmt"
-	"os"
+	"io"
+	"math"
+	"math/rand/v2"
 	"strconv"
 	"strings"
 )
 
+func init() {
+	registry.Register(registry.Puzzle{Year: 2024, Day: 13, New: func() registry.Solver { return &day13Solver{} }})
+}
+
 // day13Machine represents a machine with two buttons and a prize location.
 // Each button press moves the player in a specific X,Y direction.
 // The goal is to reach the prize location with the minimum cost.
@@ -30,42 +41,55 @@ type day13Run struct {
 	totalCost      int64 // Total cost (button A costs 3, button B costs 1)
 }
 
-// newMachine creates a new machine from a configuration string.
-// The configuration string format is:
+// day13MaxPresses is the most times a button may be pressed to win a prize
+const day13MaxPresses = 100
+
+// day13Templates are the lines of a machine configuration, in order
+var day13Templates = []string{
+	"Button A: X+%d, Y+%d",
+	"Button B: X+%d, Y+%d",
+	"Prize: X=%d, Y=%d",
+}
+
+// newMachine creates a new machine from a configuration stanza.
+// The stanza format is:
 // Button A: X+n1, Y+n2
 // Button B: X+n3, Y+n4
 // Prize: X=n5, Y=n6
-func newMachine(configStanza string) *day13Machine {
+//
+// A malformed stanza, or a button that does not move the claw forward,
+// gives a *input.ParseError.
+func newMachine(stanza []parse.Line) (*day13Machine, error) {
 	m := &day13Machine{}
-	lines := strings.Split(configStanza, "\n")
-
-	for _, line := range lines {
-		if strings.HasPrefix(line, "Button A:") {
-			parts := strings.Split(line, " ")
-			m.buttonAX = parseInt(parts[2][2 : len(parts[2])-1])
-			m.buttonAY = parseInt(parts[3][2:])
-		} else if strings.HasPrefix(line, "Button B:") {
-			parts := strings.Split(line, " ")
-			m.buttonBX = parseInt(parts[2][2 : len(parts[2])-1])
-			m.buttonBY = parseInt(parts[3][2:])
-		} else if strings.HasPrefix(line, "Prize:") {
-			parts := strings.Split(line, " ")
-			m.prizeX = parseInt(parts[1][2 : len(parts[1])-1])
-			m.prizeY = parseInt(parts[2][2:])
+	if len(stanza) != len(day13Templates) {
+		return nil, stanza[0].Error("a machine of three lines, Button A, Button B and Prize")
+	}
+
+	values := [][]*int{
+		{&m.buttonAX, &m.buttonAY},
+		{&m.buttonBX, &m.buttonBY},
+		{&m.prizeX, &m.prizeY},
+	}
+	for i, l := range stanza {
+		fields, err := parse.Match(l.Field, day13Templates[i])
+		if err != nil {
+			return nil, input.WithLine(err, l.Number)
+		}
+		for k, f := range fields {
+			if *values[i][k], err = f.Int(); err != nil {
+				return nil, input.WithLine(err, l.Number)
+			}
+			// Part 1 divides by the moves of the buttons, which must move the claw
+			if i < 2 && *values[i][k] < 1 {
+				return nil, input.WithLine(f.Error(lint.Range(1, math.MaxInt)), l.Number)
+			}
 		}
 	}
 	m.possibleRuns = make([]*day13Run, 0)
-	return m
+	return m, nil
 }
 
-// parseInt converts a string to an integer, ignoring errors.
-// Used for parsing configuration values.
-func parseInt(s string) int {
-	n, _ := strconv.Atoi(s)
-	return n
-}
-
-// Day13 solves the Day 13 puzzle of Advent of Code 2024.
+// day13Solver solves the Day 13 puzzle of Advent of Code 2024.
 // The puzzle involves finding the optimal way to reach a prize location
 // by pressing two buttons (A and B) that move in different directions.
 // Button A costs 3 units and Button B costs 1 unit.
@@ -73,81 +97,132 @@ func parseInt(s string) int {
 // 1. Find all possible combinations of button presses that reach the prize
 // 2. Find the combination with the lowest total cost
 // 3. Sum up the lowest costs across all machines
-func Day13() {
-	fmt.Println("2024 Day 13 start")
+//
+// Only part 1 is solved.
+type day13Solver struct {
+	registry.Logs
+	machines []*day13Machine // Claw machines in input order
+}
 
-	// Read input file
-	file, err := os.Open("../inputs/input13.txt")
+// Parse reads the machine configurations, one stanza per machine
+func (d *day13Solver) Parse(r io.Reader) error {
+	// Parse each stanza, a machine configuration, into a machine
+	machines := make([]*day13Machine, 0)
+	err := parse.EachStanza(r, func(stanza []parse.Line) error {
+		machine, err := newMachine(stanza)
+		if err != nil {
+			return err
+		}
+		machines = append(machines, machine)
+		return nil
+	})
 	if err != nil {
-		fmt.Println("Error opening file:", err)
-		return
+		return err
 	}
-	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
-	var inputMemory strings.Builder
-	for scanner.Scan() {
-		inputMemory.WriteString(scanner.Text() + "\n")
-	}
+	d.Log().Debug("machines", "count", len(machines))
 
-	if err := scanner.Err(); err != nil {
-		fmt.Println("Error reading file:", err)
-		return
+	for _, machine := range machines {
+		d.Log().Debug("machine", "buttonA", []int{machine.buttonAX, machine.buttonAY},
+			"buttonB", []int{machine.buttonBX, machine.buttonBY}, "prize", []int{machine.prizeX, machine.prizeY})
 	}
 
-	// Test data
-	inputMemory.Reset()
-	inputMemory.WriteString("Button A: X+94, Y+34\n" +
-		"Button B: X+22, Y+67\n" +
-		"Prize: X=8400, Y=5400\n" +
-		"\n" +
-		"Button A: X+26, Y+66\n" +
-		"Button B: X+67, Y+21\n" +
-		"Prize: X=12748, Y=12176\n" +
-		"\n" +
-		"Button A: X+17, Y+86\n" +
-		"Button B: X+84, Y+37\n" +
-		"Prize: X=7870, Y=6450\n" +
-		"\n" +
-		"Button A: X+69, Y+23\n" +
-		"Button B: X+27, Y+71\n" +
-		"Prize: X=18641, Y=10279")
-
-	fmt.Printf("inputMemory size: %d\n", inputMemory.Len())
-
-	// Split input into stanzas, each representing a machine configuration
-	inputStanzas := strings.Split(strings.TrimSpace(inputMemory.String()), "\n\n")
-
-	// Print inputStanzas for verification
-	fmt.Println("\ninputStanzas:")
-	for _, stanza := range inputStanzas {
-		fmt.Printf("%s\n\n", stanza)
+	d.machines = machines
+	return nil
+}
+
+// Lint checks that every machine is three lines, Button A, Button B and Prize,
+// separated by blank lines, that buttons move the claw and prizes are not behind it
+func (d *day13Solver) Lint(r io.Reader) (lint.Problems, error) {
+	lines, err := parse.Lines(r)
+	if err != nil {
+		return nil, err
 	}
+	least := []int{1, 1, 0} // Buttons move the claw, prizes may be at the start
 
-	// Parse inputStanzas into machines
-	machines := make([]*day13Machine, 0)
-	for _, stanza := range inputStanzas {
-		machine := newMachine(stanza)
-		machines = append(machines, machine)
+	var problems lint.Problems
+	lint.NotEmpty(&problems, lines, "a machine of three lines, Button A, Button B and Prize")
+	for _, stanza := range parse.Stanzas(lines) {
+		next := 0 // Template of the next line of the machine
+		for _, l := range stanza {
+			if next == len(day13Templates) {
+				problems.Add(l.Number, l.Field, "a blank line after the prize")
+				continue
+			}
+			// A line of a later kind means the lines before it are missing
+			for k := next + 1; k < len(day13Templates); k++ {
+				name, _, _ := strings.Cut(day13Templates[k], ":")
+				if strings.HasPrefix(l.Text, name+":") {
+					problems.Add(l.Number, input.Field{Column: 1}, strconv.Quote(day13Templates[next]))
+					next = k
+					break
+				}
+			}
+			fields, err := parse.Match(l.Field, day13Templates[next])
+			if err != nil {
+				problems.AddError(l.Number, err)
+			}
+			for _, f := range fields {
+				lint.Int(&problems, l.Number, f, least[next], math.MaxInt)
+			}
+			next++
+		}
+		if next < len(day13Templates) {
+			problems.Add(stanza[len(stanza)-1].Number+1, input.Field{Column: 1}, strconv.Quote(day13Templates[next]))
+		}
 	}
+	return problems, nil
+}
 
-	// Print machines for verification
-	fmt.Println("\nmachines:")
-	for _, machine := range machines {
-		fmt.Printf("Button A: %d, %d\n", machine.buttonAX, machine.buttonAY)
-		fmt.Printf("Button B: %d, %d\n", machine.buttonBX, machine.buttonBY)
-		fmt.Printf("Prize: %d, %d\n\n", machine.prizeX, machine.prizeY)
+// Generate writes Size machines, 320 by default like the real input, whose
+// buttons move the claw 10 to 99 along each axis. Density is the share of
+// machines whose prize is reached by 0 to 100 presses of each button, 0.5 by
+// default. The other prizes lie anywhere from 1000 to 20000 along each axis
+// and are most likely out of reach.
+func (d *day13Solver) Generate(w io.Writer, rng *rand.Rand, opts gen.Options) error {
+	opts = opts.Defaults(320, 0.5)
+	for i := range opts.Size {
+		ax, ay := gen.Between(rng, 10, 99), gen.Between(rng, 10, 99)
+		bx, by := gen.Between(rng, 10, 99), gen.Between(rng, 10, 99)
+		px, py := gen.Between(rng, 1000, 20000), gen.Between(rng, 1000, 20000)
+		if gen.Chance(rng, opts.Density) {
+			a, b := gen.Between(rng, 0, 100), gen.Between(rng, 0, 100)
+			px, py = a*ax+b*bx, a*ay+b*by
+		}
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+		lines := [][2]int{{ax, ay}, {bx, by}, {px, py}}
+		for k, template := range day13Templates {
+			if _, err := fmt.Fprintf(w, template+"\n", lines[k][0], lines[k][1]); err != nil {
+				return err
+			}
+		}
 	}
+	return nil
+}
+
+// Part1 returns the fewest tokens needed to win every winnable prize
+func (d *day13Solver) Part1(ctx context.Context) (int64, error) {
+	machines := d.machines
 
 	// Calculate possible runs for each machine
-	// For each possible number of A button presses:
+	// For each possible number of A button presses, up to day13MaxPresses
+	// and as long as the claw is not past the prize:
 	// 1. Calculate required B button presses to reach prize X coordinate
 	// 2. Verify if those button presses also reach prize Y coordinate
 	// 3. If valid, calculate total cost and add to possible runs
 	for _, machine := range machines {
-		for buttonAPresses := 0; buttonAPresses < machine.prizeX/machine.buttonAX; buttonAPresses++ {
+		machine.possibleRuns = machine.possibleRuns[:0]
+		maxAPresses := min(day13MaxPresses, machine.prizeX/machine.buttonAX)
+		for buttonAPresses := 0; buttonAPresses <= maxAPresses; buttonAPresses++ {
 			xPos := buttonAPresses * machine.buttonAX
 			buttonBPresses := (machine.prizeX - xPos) / machine.buttonBX
+			if buttonBPresses > day13MaxPresses {
+				continue
+			}
 
 			// Verify this combination reaches both X and Y coordinates
 			if (buttonAPresses*machine.buttonAX)+(buttonBPresses*machine.buttonBX) != machine.prizeX {
@@ -166,17 +241,10 @@ func Day13() {
 		}
 	}
 
-	// Print possible runs for each machine
-	fmt.Println("\npossible runs:")
 	for _, machine := range machines {
-		fmt.Printf("Machine AX,Y BX,Y PX,Y: A %d,%d B %d,%d P %d,%d\n",
-			machine.buttonAX, machine.buttonAY,
-			machine.buttonBX, machine.buttonBY,
-			machine.prizeX, machine.prizeY)
-
 		for _, run := range machine.possibleRuns {
-			fmt.Printf("Button A: %d, Button B: %d, Total Cost: %d\n",
-				run.buttonAPresses, run.buttonBPresses, run.totalCost)
+			d.Log().Debug("possible run", "prize", []int{machine.prizeX, machine.prizeY},
+				"buttonA", run.buttonAPresses, "buttonB", run.buttonBPresses, "totalCost", run.totalCost)
 		}
 	}
 
@@ -195,7 +263,12 @@ func Day13() {
 			allCosts += winner.totalCost
 		}
 	}
-	fmt.Printf("\nMachine with winner: %d, Total Cost: %d\n", machineWithWinner, allCosts)
+	d.Log().Info("machines with a winner", "count", machineWithWinner, "machines", len(machines))
+
+	return allCosts, nil
+}
 
-	fmt.Println("2024 Day 13 end")
+// Part2 is not solved yet
+func (d *day13Solver) Part2(ctx context.Context) (int64, error) {
+	return 0, registry.ErrNotImplemented
 }
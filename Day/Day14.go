@@ -2,13 +2,27 @@
 package Day
 
 import (
-	"bufio"
+	"adventcode2024/anim"
+	"adventcode2024/gen"
+	"adventcode2024/input"
+	"adventcode2024/lint"
+	"adventcode2024/logging"
+	"adventcode2024/registry"
+	"adventcode2024/utils/grid"
+	"adventcode2024/utils/parse"
+	"context"
 	"fmt"
-	"os"
+	"io"
+	"log/slog"
+	"math"
+	"math/rand/v2"
 	"strconv"
-	"strings"
 )
 
+func init() {
+	registry.Register(registry.Puzzle{Year: 2024, Day: 14, New: func() registry.Solver { return &day14Solver{} }})
+}
+
 // day14Robot represents a robot with position and velocity.
 // Each robot moves in a fixed direction and wraps around the room boundaries.
 // Multiple robots can occupy the same position.
@@ -31,162 +45,213 @@ func newRobot(px, py, vx, vy int) *day14Robot {
 	}
 }
 
-// printRoom prints the current state of the room.
+// roomText draws the current state of the room.
 // The room is displayed as a grid where:
 // - '.' represents an empty cell
 // - Numbers represent how many robots are in that cell
-func printRoom(roomHeight, roomWidth int, room [][]int) {
-	fmt.Println("Room:")
-	for j := 0; j < roomHeight; j++ {
-		for i := 0; i < roomWidth; i++ {
-			if room[i][j] == 0 {
-				fmt.Print(". ")
-			} else {
-				fmt.Printf("%d ", room[i][j])
-			}
+func roomText(room *grid.Grid[int]) string {
+	return room.Render(func(_ grid.Point, robots int) string {
+		if robots == 0 {
+			return ". "
 		}
-		fmt.Println()
+		return fmt.Sprintf("%d ", robots)
+	})
+}
+
+// renderRoom draws the room for the debug log when the record is written, not when it is logged
+func renderRoom(room *grid.Grid[int]) slog.LogValuer {
+	return logging.Lazy(func() any { return roomText(room) })
+}
+
+// placeRobots counts the robots on each cell of the room
+func placeRobots(room *grid.Grid[int], robots []*day14Robot) {
+	room.Fill(0)
+	for _, robot := range robots {
+		*room.Ptr(robot.pos())++
 	}
 }
 
-// Day14 solves the Day 14 puzzle of Advent of Code 2024.
+// pos returns the grid position of the robot, y is the row and x the column
+func (robot *day14Robot) pos() grid.Point {
+	return grid.Point{Row: robot.py, Col: robot.px}
+}
+
+// day14Template is a robot line, its position followed by its velocity
+const day14Template = "p=%d,%d v=%d,%d"
+
+// Rooms the robots move in, the room of the real input and the smaller room of the example
+const (
+	day14Width, day14Height               = 101, 103
+	day14ExampleWidth, day14ExampleHeight = 11, 7
+)
+
+// day14Solver solves the Day 14 puzzle of Advent of Code 2024.
 // The puzzle involves simulating robots moving in a room:
 // 1. Each robot has a fixed velocity and wraps around room boundaries
 // 2. Multiple robots can occupy the same position
 // 3. After simulation, the room is divided into quadrants
 // 4. The answer is the product of robot counts in each quadrant
-func Day14() {
-	fmt.Println("2024 Day 14 start")
-
-	// Read input file
-	file, err := os.Open("../inputs/input14.txt")
-	if err != nil {
-		fmt.Println("Error opening file:", err)
-		return
-	}
-	defer file.Close()
+//
+// Only part 1 is solved.
+type day14Solver struct {
+	registry.Logs
+	anim.Recorder
+	example bool          // The input is the example from the puzzle text, in the smaller room
+	robots  []*day14Robot // Robots at their starting positions
+}
 
-	scanner := bufio.NewScanner(file)
-	var inputMemory strings.Builder
-	for scanner.Scan() {
-		inputMemory.WriteString(scanner.Text() + "\n")
-	}
+// SetExample picks the room of the example, 11 tiles wide and 7 tall, instead of the real one
+func (d *day14Solver) SetExample(example bool) {
+	d.example = example
+}
 
-	if err := scanner.Err(); err != nil {
-		fmt.Println("Error reading file:", err)
-		return
+// room returns the width and height of the room the robots move in
+func (d *day14Solver) room() (int, int) {
+	if d.example {
+		return day14ExampleWidth, day14ExampleHeight
 	}
+	return day14Width, day14Height
+}
 
-	// Test data - format: "p=x,y v=vx,vy" for each robot
-	inputMemory.Reset()
-	inputMemory.WriteString("p=0,4 v=3,-3\n" +
-		"p=6,3 v=-1,-3\n" +
-		"p=10,3 v=-1,2\n" +
-		"p=2,0 v=2,-1\n" +
-		"p=0,0 v=1,3\n" +
-		"p=3,0 v=-2,-2\n" +
-		"p=7,6 v=-1,-3\n" +
-		"p=3,0 v=-1,-2\n" +
-		"p=9,3 v=2,3\n" +
-		"p=7,3 v=-1,2\n" +
-		"p=2,4 v=2,-3\n" +
-		"p=9,5 v=-3,-3")
-
-	fmt.Printf("inputMemory size: %d\n", inputMemory.Len())
-
+// Parse reads one robot per line, each starting inside the room
+func (d *day14Solver) Parse(r io.Reader) error {
 	// Parse input into robots
 	// Format: "p=x,y v=vx,vy" where:
 	// - x,y is the initial position
 	// - vx,vy is the velocity vector
 	robots := make([]*day14Robot, 0)
-	for _, line := range strings.Split(strings.TrimSpace(inputMemory.String()), "\n") {
-		parts := strings.Split(line, " ")
-		positionParts := strings.Split(parts[0], ",")
-		velocityParts := strings.Split(parts[1], ",")
-
-		px := day14ParseInt(positionParts[0][2:])
-		py := day14ParseInt(positionParts[1])
-		vx := day14ParseInt(velocityParts[0][2:])
-		vy := day14ParseInt(velocityParts[1])
-
+	width, height := d.room()
+	err := parse.Each(r, func(l parse.Line) error {
+		fields, err := parse.Match(l.Field, day14Template)
+		if err != nil {
+			return input.WithLine(err, l.Number)
+		}
+		var values [4]int
+		for i, f := range fields {
+			if values[i], err = f.Int(); err != nil {
+				return input.WithLine(err, l.Number)
+			}
+		}
+		px, py, vx, vy := values[0], values[1], values[2], values[3]
+		if px < 0 || px >= width {
+			return input.WithLine(fields[0].Error(lint.Range(0, width-1)), l.Number)
+		}
+		if py < 0 || py >= height {
+			return input.WithLine(fields[1].Error(lint.Range(0, height-1)), l.Number)
+		}
 		robots = append(robots, newRobot(px, py, vx, vy))
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	// Print initial robot positions and velocities
-	fmt.Println("Robots:")
 	for _, robot := range robots {
-		fmt.Printf("P %d,%d  V %d,%d\n", robot.px, robot.py, robot.vx, robot.vy)
+		d.Log().Debug("robot", "p", []int{robot.px, robot.py}, "v", []int{robot.vx, robot.vy})
+	}
+
+	d.robots = robots
+	return nil
+}
+
+// Lint checks that every robot starts inside the room, 101 tiles wide and 103
+// tall or 11 by 7 for the example. Any speed is accepted, as by Parse.
+func (d *day14Solver) Lint(r io.Reader) (lint.Problems, error) {
+	lines, err := parse.Lines(r)
+	if err != nil {
+		return nil, err
+	}
+	var problems lint.Problems
+	lint.NotEmpty(&problems, lines, strconv.Quote(day14Template))
+
+	width, height := d.room()
+	for _, l := range lines {
+		fields, err := parse.Match(l.Field, day14Template)
+		if err != nil {
+			problems.AddError(l.Number, err)
+			continue
+		}
+		lint.Int(&problems, l.Number, fields[0], 0, width-1)
+		lint.Int(&problems, l.Number, fields[1], 0, height-1)
+		lint.Int(&problems, l.Number, fields[2], math.MinInt, math.MaxInt)
+		lint.Int(&problems, l.Number, fields[3], math.MinInt, math.MaxInt)
+	}
+	return problems, nil
+}
+
+// Generate writes Size robots, 500 by default like the real input, anywhere in
+// the room of the real input, 101 tiles wide and 103 tall. Density is how
+// fast the robots move as a share of the room, 1 by default, a robot moving
+// up to that share of the room's width and height each second.
+func (d *day14Solver) Generate(w io.Writer, rng *rand.Rand, opts gen.Options) error {
+	opts = opts.Defaults(500, 1)
+	const width, height = day14Width, day14Height
+	maxVX, maxVY := int(opts.Density*(width-1)), int(opts.Density*(height-1))
+	for range opts.Size {
+		px, py := rng.IntN(width), rng.IntN(height)
+		vx, vy := gen.Between(rng, -maxVX, maxVX), gen.Between(rng, -maxVY, maxVY)
+		if _, err := fmt.Fprintf(w, day14Template+"\n", px, py, vx, vy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Part1 returns the safety factor after 100 seconds, the product of the robot counts per quadrant
+func (d *day14Solver) Part1(ctx context.Context) (int64, error) {
+	// Move copies so the parsed starting positions stay intact
+	robots := make([]*day14Robot, len(d.robots))
+	for i, robot := range d.robots {
+		robots[i] = newRobot(robot.px, robot.py, robot.vx, robot.vy)
 	}
 
 	// Define room dimensions
-	roomWidth := 11
-	roomHeight := 7
+	roomWidth, roomHeight := d.room()
 
 	// Create empty room grid
-	rooms := make([][]int, roomWidth)
-	for i := range rooms {
-		rooms[i] = make([]int, roomHeight)
-	}
+	rooms := grid.New[int](roomHeight, roomWidth)
 
 	// Mark initial robot positions in room
-	for _, robot := range robots {
-		rooms[robot.px][robot.py]++
-	}
+	placeRobots(rooms, robots)
 
-	// Print initial room state
-	printRoom(roomHeight, roomWidth, rooms)
+	d.Log().Debug("initial room", "room", renderRoom(rooms))
 
 	// Simulate robot movement for specified number of steps
 	// Each step:
 	// 1. Update position based on velocity
 	// 2. Handle wrapping around room boundaries
+	// 3. Emit a frame of the room when recorded
 	steps := 100
-	for _, robot := range robots {
-		for i := 1; i <= steps; i++ {
-			robot.px += robot.vx
-			robot.py += robot.vy
-
-			// Handle wrapping around room boundaries
-			if robot.px < 0 {
-				robot.px = roomWidth + robot.px
-			}
-			if robot.px >= roomWidth {
-				robot.px = robot.px - roomWidth
-			}
-			if robot.py < 0 {
-				robot.py = roomHeight + robot.py
-			}
-			if robot.py >= roomHeight {
-				robot.py = robot.py - roomHeight
-			}
+	for i := 1; i <= steps; i++ {
+		for _, robot := range robots {
+			// Move and wrap around the room boundaries, however fast the robot is
+			robot.px = ((robot.px+robot.vx)%roomWidth + roomWidth) % roomWidth
+			robot.py = ((robot.py+robot.vy)%roomHeight + roomHeight) % roomHeight
 		}
+
+		d.Emit(func() anim.Frame {
+			placeRobots(rooms, robots)
+			return anim.Frame{Text: roomText(rooms), Label: fmt.Sprintf("second %d", i)}
+		})
 	}
 
 	// Reset room and update with final robot positions
-	for i := range rooms {
-		for j := range rooms[i] {
-			rooms[i][j] = 0
-		}
-	}
-	for _, robot := range robots {
-		rooms[robot.px][robot.py]++
-	}
+	placeRobots(rooms, robots)
 
 	// Calculate robot count in each quadrant
 	// Room is divided into four quadrants by the center point
 	quadrantRobotCount := make([]int, 4)
-	for i := 0; i < roomWidth; i++ {
-		for j := 0; j < roomHeight; j++ {
-			if rooms[i][j] > 0 {
-				if i < roomWidth/2 && j < roomHeight/2 {
-					quadrantRobotCount[0] += rooms[i][j] // Top-left quadrant
-				} else if i > roomWidth/2 && j < roomHeight/2 {
-					quadrantRobotCount[1] += rooms[i][j] // Top-right quadrant
-				} else if i < roomWidth/2 && j > roomHeight/2 {
-					quadrantRobotCount[2] += rooms[i][j] // Bottom-left quadrant
-				} else if i > roomWidth/2 && j > roomHeight/2 {
-					quadrantRobotCount[3] += rooms[i][j] // Bottom-right quadrant
-				}
+	for pos, count := range rooms.All() {
+		i, j := pos.Col, pos.Row
+		if count > 0 {
+			if i < roomWidth/2 && j < roomHeight/2 {
+				quadrantRobotCount[0] += count // Top-left quadrant
+			} else if i > roomWidth/2 && j < roomHeight/2 {
+				quadrantRobotCount[1] += count // Top-right quadrant
+			} else if i < roomWidth/2 && j > roomHeight/2 {
+				quadrantRobotCount[2] += count // Bottom-left quadrant
+			} else if i > roomWidth/2 && j > roomHeight/2 {
+				quadrantRobotCount[3] += count // Bottom-right quadrant
 			}
 		}
 	}
@@ -197,15 +262,12 @@ func Day14() {
 		answer *= count
 	}
 
-	// Print final room state and answer
-	printRoom(roomHeight, roomWidth, rooms)
-	fmt.Printf("Answer: %d\n", answer)
-	fmt.Println("2024 Day 14 end")
+	d.Log().Debug("final room", "room", renderRoom(rooms), "quadrants", quadrantRobotCount)
+
+	return int64(answer), nil
 }
 
-// day14ParseInt converts a string to an integer, ignoring errors.
-// Used for parsing robot position and velocity values.
-func day14ParseInt(s string) int {
-	n, _ := strconv.Atoi(s)
-	return n
+// Part2 is not solved yet
+func (d *day14Solver) Part2(ctx context.Context) (int64, error) {
+	return 0, registry.ErrNotImplemented
 }
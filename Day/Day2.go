@@ -1,69 +1,115 @@
 package Day
 
 import (
-	"bufio"
-	"fmt"
+	"adventcode2024/gen"
+	"adventcode2024/input"
+	"adventcode2024/lint"
+	"adventcode2024/registry"
+	"adventcode2024/utils/parse"
+	"context"
+	"io"
+	"log/slog"
 	"math"
-	"os"
+	"math/rand/v2"
 	"strconv"
-	"strings"
 )
 
-// Day2 solves the Advent of Code 2024 Day 2 puzzle
+func init() {
+	registry.Register(registry.Puzzle{Year: 2024, Day: 2, New: func() registry.Solver { return &day2Solver{} }})
+}
+
+// day2Solver solves the Advent of Code 2024 Day 2 puzzle
 // The puzzle involves analyzing sequences of numbers to determine if they are "safe"
 // Part 1: A sequence is safe if it's either strictly increasing or decreasing AND
 //
 //	the difference between consecutive numbers is ≤ 3
 //
 // Part 2: Similar to part 1 but allows one number to be removed to make the sequence safe
-func Day2() {
-	fmt.Println("2024 Day2 start")
+type day2Solver struct {
+	registry.Logs
+	inputArray [][]int64 // One report of levels per input line
+}
 
-	// Open file and gather raw inputs
+// Parse reads one report of levels per line
+func (d *day2Solver) Parse(r io.Reader) error {
+	// Parse input line by line
 	inputArray := [][]int64{}
-	var inputFileNameBegins = "input" // "input" or "test"
-	file, err := os.Open(fmt.Sprintf("../inputs/%s2.txt", inputFileNameBegins))
+	err := parse.Each(r, func(l parse.Line) error {
+		return input.WithLine(gatherInputs2(l.Field, &inputArray), l.Number)
+	})
 	if err != nil {
-		fmt.Println(err)
-		return
+		return err
 	}
-	defer file.Close()
 
-	// Read input file line by line
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := scanner.Text()
-		gatherInputs2(line, &inputArray)
-	}
+	d.inputArray = inputArray
+	return nil
+}
 
-	if err := scanner.Err(); err != nil {
-		fmt.Println(err)
-		return
+// Lint checks that every line is a report of at least one level of 0 or more
+func (d *day2Solver) Lint(r io.Reader) (lint.Problems, error) {
+	lines, err := parse.Lines(r)
+	if err != nil {
+		return nil, err
 	}
+	var problems lint.Problems
+	lint.NotEmpty(&problems, lines, "levels separated by spaces")
+	for _, l := range lines {
+		fields := l.Fields()
+		if len(fields) == 0 {
+			problems.Add(l.Number, l.Field, "levels separated by spaces")
+		}
+		for _, f := range fields {
+			lint.Int(&problems, l.Number, f, 0, math.MaxInt)
+		}
+	}
+	return problems, nil
+}
 
-	fmt.Println("inputArray size:", len(inputArray))
-
-	// Process the inputs
-	safeCount := 0
-	safeCount2 := 0
-	for _, row := range inputArray {
-		// Uncomment to use part 1
-		// safeCount += processInputs2(row)
-
-		// For part 2, try both with and without removing a number
-		row2 := make([]int64, len(row))
-		copy(row2, row)
-		safe2 := processInputs2pt2(row, true)
-		if safe2 == 0 && len(row) < len(row2) {
-			safe2 = processInputs2pt2(row, false)
+// Generate writes Size reports, 1000 by default like the real input, of 5 to 8
+// levels from 1 to 99. Each report rises or falls, and Density is the chance
+// that a step between two levels is a safe one of 1 to 3, 0.95 by default;
+// other steps jump anywhere.
+func (d *day2Solver) Generate(w io.Writer, rng *rand.Rand, opts gen.Options) error {
+	opts = opts.Defaults(1000, 0.95)
+	for range opts.Size {
+		direction := 1
+		if rng.IntN(2) == 0 {
+			direction = -1
+		}
+		level := gen.Between(rng, 1, 99)
+		line := strconv.Itoa(level)
+		for range gen.Between(rng, 4, 7) {
+			if gen.Chance(rng, opts.Density) {
+				level += direction * gen.Between(rng, 1, 3)
+			} else {
+				level = gen.Between(rng, 1, 99)
+			}
+			level = max(1, min(99, level))
+			line += " " + strconv.Itoa(level)
+		}
+		if _, err := io.WriteString(w, line+"\n"); err != nil {
+			return err
 		}
-		safeCount2 += safe2
 	}
+	return nil
+}
 
-	fmt.Println("safeCount:", safeCount)
-	fmt.Println("safeCount2:", safeCount2)
+// Part1 returns the number of safe reports
+func (d *day2Solver) Part1(ctx context.Context) (int64, error) {
+	safeCount := 0
+	for _, row := range d.inputArray {
+		safeCount += processInputs2(row)
+	}
+	return int64(safeCount), nil
+}
 
-	fmt.Println("2024 Day2 end")
+// Part2 returns the number of reports that are safe after removing at most one level
+func (d *day2Solver) Part2(ctx context.Context) (int64, error) {
+	safeCount2 := 0
+	for _, row := range d.inputArray {
+		safeCount2 += processInputs2pt2(d.Log(), row, true)
+	}
+	return int64(safeCount2), nil
 }
 
 // processInputs2 checks if a sequence of numbers is "safe" according to part 1 rules
@@ -116,106 +162,50 @@ func processInputs2(rowList []int64) int {
 }
 
 // processInputs2pt2 checks if a sequence of numbers is "safe" according to part 2 rules
-// Similar to part 1 but allows one number to be removed to make the sequence safe
+// Similar to part 1 but allows one number to be removed to make the sequence safe.
+// Every number is tried in turn, since the one to remove is not always where
+// the sequence first turns unsafe.
 // Parameters:
-//   - rowList: The sequence of numbers to check
+//   - log: Logger for the sequences found unsafe
+//   - rowList: The sequence of numbers to check, left unchanged
 //   - allowForgive: Whether to allow removing one number to make the sequence safe
 //
 // Returns:
 //   - 1 if the sequence is safe (or can be made safe by removing one number)
 //   - 0 if the sequence is not safe
-func processInputs2pt2(rowList []int64, allowForgive bool) int {
-	forgiveRowNum := -1
-	isSafeRisingOrFalling := 0
-
-	// Check if sequence is strictly increasing
-	for i := 0; i < len(rowList)-1; i++ {
-		if rowList[i] < rowList[i+1] {
-			isSafeRisingOrFalling = 1
-		} else {
-			if allowForgive {
-				forgiveRowNum = i + 1
-				allowForgive = false
-				isSafeRisingOrFalling = 1
-			} else {
-				isSafeRisingOrFalling = 0
-				break
-			}
-		}
+func processInputs2pt2(log *slog.Logger, rowList []int64, allowForgive bool) int {
+	if processInputs2(rowList) == 1 {
+		return 1
 	}
 
-	// If not increasing, check if it's strictly decreasing
-	if isSafeRisingOrFalling == 0 {
-		for i := 0; i < len(rowList)-1; i++ {
-			if i == forgiveRowNum {
-				continue
-			}
-			if rowList[i] > rowList[i+1] {
-				isSafeRisingOrFalling = 1
-			} else {
-				if allowForgive {
-					forgiveRowNum = i + 1
-					allowForgive = false
-					isSafeRisingOrFalling = 1
-				} else {
-					isSafeRisingOrFalling = 0
-					break
-				}
+	if allowForgive {
+		forgiven := make([]int64, 0, len(rowList))
+		for forgiveRowNum := range rowList {
+			forgiven = append(append(forgiven[:0], rowList[:forgiveRowNum]...), rowList[forgiveRowNum+1:]...)
+			if processInputs2(forgiven) == 1 {
+				return 1
 			}
 		}
 	}
 
-	// Check if differences between consecutive numbers are ≤ 3
-	isSafeGradual := 0
-	if isSafeRisingOrFalling == 1 {
-		for i := 0; i < len(rowList)-1; i++ {
-			if i == forgiveRowNum {
-				continue
-			}
-			diff := int64(math.Abs(float64(rowList[i+1] - rowList[i])))
-			if diff <= 3 {
-				isSafeGradual = 1
-			} else {
-				if allowForgive {
-					forgiveRowNum = i + 1
-					isSafeGradual = 1
-				} else {
-					isSafeGradual = 0
-					break
-				}
-			}
-		}
-	}
-
-	// Remove the forgiven number if one was found
-	if forgiveRowNum != -1 {
-		rowList = append(rowList[:forgiveRowNum], rowList[forgiveRowNum+1:]...)
-	}
-
 	// Debug output for unsafe sequences
-	if isSafeRisingOrFalling*isSafeGradual == 0 {
-		fmt.Printf("rowList: %v isSafeRisingOrFalling: %d isSafeGradual: %d forgiveRowNum: %d final: %d\n",
-			rowList, isSafeRisingOrFalling, isSafeGradual, forgiveRowNum, isSafeRisingOrFalling*isSafeGradual)
-	}
+	log.Debug("unsafe report", "rowList", rowList, "allowForgive", allowForgive)
 
-	return isSafeRisingOrFalling * isSafeGradual
+	return 0
 }
 
 // gatherInputs2 parses a line of input into a slice of integers
 // Parameters:
 //   - line: The input line to parse
 //   - inputArray: Pointer to the array where the parsed numbers will be stored
-func gatherInputs2(line string, inputArray *[][]int64) {
+//
+// Returns a *input.ParseError without a line number if a level is not a number
+func gatherInputs2(line input.Field, inputArray *[][]int64) error {
 	// Split the line by space
-	parts := strings.Fields(line)
-	rowList := []int64{}
-	for _, part := range parts {
-		num, err := strconv.ParseInt(part, 10, 64)
-		if err != nil {
-			fmt.Println("Error parsing:", err)
-			continue
-		}
-		rowList = append(rowList, num)
+	rowList, err := parse.Int64List(line, "")
+	if err != nil {
+		return err
 	}
 	*inputArray = append(*inputArray, rowList)
+	return nil
 }
@@ -1,95 +1,225 @@
 package Day
 
 import (
+	"adventcode2024/lint"
+	"adventcode2024/registry"
+	"adventcode2024/utils/parse"
 	"bufio"
-	"fmt"
-	"os"
-	"regexp"
-	"strconv"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"math"
+	"strings"
 )
 
-// Day3 is the entry point for Day 3 solution
-// It reads input, processes it, and outputs results
+func init() {
+	registry.Register(registry.Puzzle{Year: 2024, Day: 3, New: func() registry.Solver { return &day3Solver{} }})
+}
+
+// day3Solver is the solver for Day 3
 // The puzzle involves processing mathematical expressions in a string format
 // Part 1: Calculate sum of all mul(num1,num2) expressions
 // Part 2: Calculate sum of mul(num1,num2) expressions between do() and don't() tokens
-func Day3() {
-	fmt.Println("2024 Day 3 start")
+type day3Solver struct {
+	registry.Logs
+	instructions []day3Instruction // Instructions found in the corrupted memory, in order
+}
 
-	// Get input from file
-	inputStr, done := GetInput()
-	if done {
-		return
+// day3Instruction is a mul(num1,num2), do() or don't() found in the corrupted memory
+type day3Instruction struct {
+	op         string // "mul", "do" or "don't"
+	num1, num2 int64  // Operands of a mul
+	overflow   bool   // A number of the mul overflows an int64, the mul is skipped
+}
+
+// Parse scans the corrupted memory for the instructions shared by both parts
+func (d *day3Solver) Parse(r io.Reader) error {
+	instructions, err := day3Scan(d.Log(), r)
+	if err != nil {
+		return err
 	}
+	d.instructions = instructions
+	return nil
+}
 
-	// Print input size and content for debugging
-	fmt.Printf("inputMemory size: %d\n", len(inputStr))
-	fmt.Printf("inputMemory: %s\n", inputStr)
+// Lint checks that there is memory to scan, any text is valid corrupted memory
+func (d *day3Solver) Lint(r io.Reader) (lint.Problems, error) {
+	lines, err := parse.Lines(r)
+	if err != nil {
+		return nil, err
+	}
+	var problems lint.Problems
+	lint.NotEmpty(&problems, lines, "corrupted memory")
+	return problems, nil
+}
 
-	// Uncomment to run part 1
-	totalPt1 := getTotalPt1(inputStr)
-	fmt.Printf("total Pt1: %d\n", totalPt1)
+// Part1 returns the sum of every mul(num1,num2) product
+func (d *day3Solver) Part1(ctx context.Context) (int64, error) {
+	return getTotalPt1(d.Log(), d.instructions), nil
+}
 
-	// Run part 2
-	totalPt2 := getTotalPt2(inputStr)
-	fmt.Printf("total Pt2: %d\n", totalPt2)
+// Part2 returns the sum of the mul(num1,num2) products enabled by do() and don't()
+func (d *day3Solver) Part2(ctx context.Context) (int64, error) {
+	return getTotalPt2(d.Log(), d.instructions), nil
+}
 
-	fmt.Println("2024 Day 3 end")
+// day3Scan reads corrupted memory from r and returns the instructions in it.
+// The memory is matched one byte at a time, so only the instructions are kept
+// however large the input is. Line breaks are skipped, an instruction may
+// continue on the next line. A mul whose numbers overflow an int64 is skipped
+// with a warning.
+func day3Scan(log *slog.Logger, r io.Reader) ([]day3Instruction, error) {
+	var instructions []day3Instruction
+	var m day3Matcher
+	br := bufio.NewReader(r)
+	for {
+		c, err := br.ReadByte()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if c == '\n' {
+			continue
+		}
+		if c == '\r' {
+			if next, err := br.Peek(1); err == nil && next[0] == '\n' {
+				continue
+			}
+		}
+		in, ok := m.feed(c)
+		if !ok {
+			continue
+		}
+		if in.overflow {
+			log.Warn("skipping mul with an out of range number")
+			continue
+		}
+		instructions = append(instructions, in)
+	}
+	return instructions, nil
 }
 
-// GetInput reads the input file and returns its contents as a string
-// Returns:
-//   - string: The contents of the input file
-//   - bool: true if there was an error, false otherwise
-func GetInput() (string, bool) {
-	// Read the entire input file into memory
-	var inputFileNameBegins = "test" // "input" or "test"
-	file, err := os.Open(fmt.Sprintf("../inputs/%s3.txt", inputFileNameBegins))
-	if err != nil {
-		fmt.Printf("Error reading file: %v\n", err)
-		return "", true
+// day3State is how much of an instruction day3Matcher has matched
+type day3State int
+
+const (
+	day3None  day3State = iota // Nothing
+	day3Word                   // A prefix of "mul(", "do()" or "don't()"
+	day3Open                   // "mul("
+	day3Num1                   // "mul(" and the digits of num1
+	day3Comma                  // "mul(num1,"
+	day3Num2                   // "mul(num1," and the digits of num2
+)
+
+// day3Matcher recognises mul(num1,num2), do() and don't() in a stream of bytes,
+// with the same matches as the regular expression
+// do\(\)|don't\(\)|mul\((\d+),(\d+)\)
+type day3Matcher struct {
+	state      day3State
+	word       string // Text matched in day3Word
+	num1, num2 int64
+	overflow   bool // A number of the current mul overflows an int64
+}
+
+// feed matches one more byte. It returns an instruction when c completes one.
+func (m *day3Matcher) feed(c byte) (day3Instruction, bool) {
+	in, ok, accepted := m.step(c)
+	if !accepted {
+		// c ends a partial match. No instruction can start inside the text
+		// matched so far, so matching starts over at c itself.
+		m.reset()
+		in, ok, _ = m.step(c)
 	}
-	defer file.Close()
+	return in, ok
+}
 
-	var inputStr = ""
+// step matches c after the text matched so far. accepted is false when c
+// cannot continue a partial match.
+func (m *day3Matcher) step(c byte) (in day3Instruction, ok, accepted bool) {
+	isDigit := c >= '0' && c <= '9'
+	switch m.state {
+	case day3Open, day3Comma:
+		if !isDigit {
+			return in, false, false
+		}
+		m.state++
+		m.digit(c)
+		return in, false, true
+	case day3Num1, day3Num2:
+		switch {
+		case isDigit:
+			m.digit(c)
+			return in, false, true
+		case c == ',' && m.state == day3Num1:
+			m.state = day3Comma
+			return in, false, true
+		case c == ')' && m.state == day3Num2:
+			in = day3Instruction{op: "mul", num1: m.num1, num2: m.num2, overflow: m.overflow}
+			m.reset()
+			return in, true, true
+		}
+		return in, false, false
+	}
 
-	// Read file line by line and concatenate
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := scanner.Text()
-		inputStr += line
+	word := m.word + string(c)
+	switch word {
+	case "do()":
+		m.reset()
+		return day3Instruction{op: "do"}, true, true
+	case "don't()":
+		m.reset()
+		return day3Instruction{op: "don't"}, true, true
+	case "mul(":
+		m.state, m.word, m.num1, m.num2 = day3Open, "", 0, 0
+		return in, false, true
+	}
+	for _, instruction := range []string{"mul(", "do()", "don't()"} {
+		if strings.HasPrefix(instruction, word) {
+			m.state, m.word = day3Word, word
+			return in, false, true
+		}
 	}
+	return in, false, m.state == day3None
+}
 
-	if err := scanner.Err(); err != nil {
-		fmt.Println(err)
-		return "", true
+// digit adds a digit to the number being read
+func (m *day3Matcher) digit(c byte) {
+	n := &m.num1
+	if m.state == day3Num2 {
+		n = &m.num2
+	}
+	d := int64(c - '0')
+	if *n > (math.MaxInt64-d)/10 {
+		m.overflow = true
+		return
 	}
-	return inputStr, false
+	*n = *n*10 + d
+}
+
+// reset forgets the partial match
+func (m *day3Matcher) reset() {
+	m.state, m.word, m.overflow = day3None, "", false
 }
 
-// getTotalPt2 processes input by matching patterns and calculating the sum
-// It looks for mul(num1,num2) patterns and adds their products to a total
+// getTotalPt2 adds up the products of the mul instructions
 // The doFlag state can be toggled by do() and don't() tokens to control whether calculations are performed
 // Returns:
 //   - int64: The sum of all products between do() and don't() tokens
-func getTotalPt2(inputStr string) int64 {
-	// Regular expression to match:
-	// - do() and don't() tokens for control
-	// - mul(num1,num2) patterns with capture groups for the numbers
-	regex := regexp.MustCompile(`do\(\)|don't\(\)|mul\((\d+),(\d+)\)`)
-	matches := regex.FindAllStringSubmatch(inputStr, -1)
-
+func getTotalPt2(log *slog.Logger, instructions []day3Instruction) int64 {
 	var total int64 = 0
 	doFlag := true // true = do, false = don't
 
-	for _, match := range matches {
-		fmt.Printf("match: %s\n", match[0])
+	for _, in := range instructions {
+		log.Debug("match", "op", in.op)
 
 		// Handle control flags
-		if match[0] == "do()" {
+		if in.op == "do" {
 			doFlag = true
 			continue
-		} else if match[0] == "don't()" {
+		} else if in.op == "don't" {
 			doFlag = false
 			continue
 		}
@@ -99,59 +229,25 @@ func getTotalPt2(inputStr string) int64 {
 			continue
 		}
 
-		// Process multiplication
-		// In Go regex, the whole match is at index 0, and capture groups start at index 1
-		num1, err1 := strconv.ParseInt(match[1], 10, 64)
-		num2, err2 := strconv.ParseInt(match[2], 10, 64)
-
-		if err1 == nil && err2 == nil {
-			total += (num1 * num2)
-			// Uncomment for debugging
-			// fmt.Printf("num1: %d num2: %d total: %d\n", num1, num2, total)
-		} else {
-			if err1 != nil {
-				fmt.Printf("Error parsing num1: %v\n", err1)
-			}
-			if err2 != nil {
-				fmt.Printf("Error parsing num2: %v\n", err2)
-			}
-		}
+		total += in.num1 * in.num2
+		log.Debug("product", "num1", in.num1, "num2", in.num2, "total", total)
 	}
 
 	return total
 }
 
-// getTotalPt1 processes input by matching mul(num1,num2) patterns
-// and calculating the sum of all products
+// getTotalPt1 adds up the products of every mul instruction
 // Returns:
 //   - int64: The sum of all products found in mul(num1,num2) patterns
-func getTotalPt1(inputStr string) int64 {
-	// Regular expression to match mul(num1,num2) patterns
-	// Capture groups are used to extract the numbers
-	regex := regexp.MustCompile(`mul\((\d+),(\d+)\)`)
-	matches := regex.FindAllStringSubmatch(inputStr, -1)
-
+func getTotalPt1(log *slog.Logger, instructions []day3Instruction) int64 {
 	var total int64 = 0
 
-	for _, match := range matches {
-		fmt.Printf("match: %s\n", match[0])
-
-		// Process multiplication
-		num1, err1 := strconv.ParseInt(match[1], 10, 64)
-		num2, err2 := strconv.ParseInt(match[2], 10, 64)
-
-		if err1 == nil && err2 == nil {
-			total += (num1 * num2)
-			// Uncomment for debugging
-			// fmt.Printf("num1: %d num2: %d total: %d\n", num1, num2, total)
-		} else {
-			if err1 != nil {
-				fmt.Printf("Error parsing num1: %v\n", err1)
-			}
-			if err2 != nil {
-				fmt.Printf("Error parsing num2: %v\n", err2)
-			}
+	for _, in := range instructions {
+		if in.op != "mul" {
+			continue
 		}
+		total += in.num1 * in.num2
+		log.Debug("product", "num1", in.num1, "num2", in.num2, "total", total)
 	}
 
 	return total
@@ -1,55 +1,114 @@
 package Day
 
 import (
-	"bufio"
-	"fmt"
-	"os"
+	"adventcode2024/input"
+	"adventcode2024/lint"
+	"adventcode2024/registry"
+	"adventcode2024/utils/parse"
+	"context"
+	"io"
+	"log/slog"
+	"math"
 	"strconv"
 	"strings"
 )
 
-// Day5 solves the Advent of Code 2024 Day 5 puzzle
+func init() {
+	registry.Register(registry.Puzzle{Year: 2024, Day: 5, New: func() registry.Solver { return &day5Solver{} }})
+}
+
+// day5Solver solves the Advent of Code 2024 Day 5 puzzle
 // The puzzle involves processing page order rules and updates
 // Part 1: Find the sum of middle pages from updates that are already valid
 // Part 2: Find the sum of middle pages from updates that can be made valid by reordering
-func Day5() {
-	fmt.Println("2024 Day5 start")
+type day5Solver struct {
+	registry.Logs
+	inputRules   []string   // Page order rules in "page1|page2" form
+	inputUpdates [][]string // Pages of each update in their original order
+}
 
-	// Open file and gather raw inputs
-	inputMemory, shouldReturn := day5GetInput()
-	if shouldReturn {
-		return
+// Parse reads the page order rules and the updates
+func (d *day5Solver) Parse(r io.Reader) error {
+	inputRules, inputUpdates, err := day5ReadInput(r)
+	if err != nil {
+		return err
 	}
+	d.inputRules = inputRules
+	d.inputUpdates = inputUpdates
 
-	fmt.Println("inputMemory size:", len(inputMemory))
+	d.Log().Debug("page order rules", "count", len(d.inputRules), "inputRules", d.inputRules)
+	d.Log().Debug("updates", "count", len(d.inputUpdates), "inputUpdates", d.inputUpdates)
 
-	// Parse input into rules and updates
-	inputRules := getPageOrderRules(inputMemory)
-	inputUpdates := getUpdates(inputMemory)
+	return nil
+}
 
-	// // Print page order rules
-	// fmt.Printf("inputRules: %d\n", len(inputRules))
-	// for _, rule := range inputRules {
-	// 	fmt.Println(rule)
-	// }
+// Lint checks the rules, the blank line and the updates, which need an odd
+// number of distinct pages so that one is in the middle
+func (d *day5Solver) Lint(r io.Reader) (lint.Problems, error) {
+	lines, err := parse.Lines(r)
+	if err != nil {
+		return nil, err
+	}
+	var problems lint.Problems
+	if !lint.NotEmpty(&problems, lines, `a rule "page1|page2"`) {
+		return problems, nil
+	}
+	stanzas := parse.Stanzas(lines)
+	if len(stanzas) < 2 {
+		problems.AddInput("a blank line between the rules and the updates")
+	}
+	for i := 2; i < len(stanzas); i++ {
+		problems.Add(stanzas[i][0].Number-1, input.Field{Column: 1}, "an update")
+	}
 
-	// // Print updates
-	// fmt.Printf("\ninputUpdates: %d\n", len(inputUpdates))
-	// for _, update := range inputUpdates {
-	// 	fmt.Println(update)
-	// }
+	for _, l := range stanzas[0] {
+		pages := parse.List(l.Field, "|")
+		if len(pages) != 2 {
+			problems.Add(l.Number, l.Field, `a rule "page1|page2"`)
+			continue
+		}
+		for _, page := range pages {
+			lint.Int(&problems, l.Number, page, 0, math.MaxInt)
+		}
+	}
+	for _, stanza := range stanzas[1:] {
+		for _, l := range stanza {
+			pages := parse.List(l.Field, ",")
+			seen := make(map[string]bool)
+			for _, page := range pages {
+				if _, ok := lint.Int(&problems, l.Number, page, 0, math.MaxInt); ok && seen[page.Text] {
+					problems.Add(l.Number, page, "each page once in an update")
+				}
+				seen[page.Text] = true
+			}
+			if len(pages)%2 == 0 {
+				problems.Add(l.Number, l.Field, "an odd number of pages, so that one is in the middle")
+			}
+		}
+	}
+	return problems, nil
+}
 
-	// Process parts
-	day5part1(inputUpdates, inputRules)
-	day5part2(inputUpdates, inputRules)
+// Part1 returns the sum of the middle pages of the correctly ordered updates
+func (d *day5Solver) Part1(ctx context.Context) (int64, error) {
+	return int64(day5part1(d.Log(), d.inputUpdates, d.inputRules)), nil
+}
 
-	fmt.Println("2024 Day5 end")
+// Part2 returns the sum of the middle pages of the reordered incorrect updates
+func (d *day5Solver) Part2(ctx context.Context) (int64, error) {
+	// day5part2 reorders updates in place, so give it copies
+	inputUpdates := make([][]string, len(d.inputUpdates))
+	for i, update := range d.inputUpdates {
+		inputUpdates[i] = append([]string(nil), update...)
+	}
+	middleOfTruth, err := day5part2(ctx, d.Log(), inputUpdates, d.inputRules)
+	return int64(middleOfTruth), err
 }
 
 // day5part1 processes part 1 of the puzzle
 // Finds the sum of middle pages from updates that are already valid
 // An update is valid if all its pages are in the correct order according to the rules
-func day5part1(inputUpdates [][]string, inputRules []string) {
+func day5part1(log *slog.Logger, inputUpdates [][]string, inputRules []string) int {
 	allValid := true
 	middleOfTruth := 0
 
@@ -68,16 +127,17 @@ func day5part1(inputUpdates [][]string, inputRules []string) {
 			middleOfTruth += val
 		}
 
-		// fmt.Printf("# %v %v\n", update, allValid)
+		log.Debug("checked update", "update", update, "allValid", allValid)
 	}
 
-	fmt.Printf("middleSum %d\n", middleOfTruth)
+	return middleOfTruth
 }
 
 // day5part2 processes part 2 of the puzzle
 // Finds the sum of middle pages from updates that can be made valid by reordering
 // An update can be made valid by moving pages to positions that satisfy the rules
-func day5part2(inputUpdates [][]string, inputRules []string) {
+// The reorder loop stops with ctx's error once ctx is cancelled
+func day5part2(ctx context.Context, log *slog.Logger, inputUpdates [][]string, inputRules []string) (int, error) {
 	// Build list of invalid updates
 	invalidUpdates := make([]int, 0)
 	for j, update := range inputUpdates {
@@ -95,6 +155,9 @@ func day5part2(inputUpdates [][]string, inputRules []string) {
 	// Try to make invalid updates valid by reordering
 	allValid := false
 	for !allValid {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
 		allValid = true
 		for i := 0; i < len(invalidUpdates); i++ {
 			update := inputUpdates[invalidUpdates[i]]
@@ -113,14 +176,13 @@ func day5part2(inputUpdates [][]string, inputRules []string) {
 	middleOfTruth := 0
 	for _, invalidUpdate := range invalidUpdates {
 		update := inputUpdates[invalidUpdate]
-		// fmt.Printf("update %v", update)
 		middlePage := update[getMiddlePage(update)]
-		// fmt.Printf("  mid %s\n", middlePage)
+		log.Debug("reordered update", "update", update, "mid", middlePage)
 		val, _ := strconv.Atoi(middlePage)
 		middleOfTruth += val
 	}
 
-	fmt.Printf("middleSum %d\n", middleOfTruth)
+	return middleOfTruth, nil
 }
 
 // getMiddlePage returns the index of the middle page in an update
@@ -216,45 +278,6 @@ func validateOrder(inputRules []string, thisIndex int, update []string) bool {
 	return validOrder
 }
 
-// getPageOrderRules extracts the page order rules from the input
-// Rules are in the format "page1|page2" where page1 must come before page2
-// Returns a slice of rules, stopping at the first empty line
-func getPageOrderRules(inputMemory string) []string {
-	// Split by new line into array
-	inputRows := strings.Split(inputMemory, "\n")
-	pageOrderRulesList := make([]string, 0)
-	for _, row := range inputRows {
-		// Add rows till empty line
-		if row == "" {
-			break
-		}
-		pageOrderRulesList = append(pageOrderRulesList, row)
-	}
-	return pageOrderRulesList
-}
-
-// getUpdates extracts the updates from the input
-// Updates are comma-separated numbers representing page numbers
-// Returns a slice of updates, starting after the first empty line
-func getUpdates(inputMemory string) [][]string {
-	// Split by new line into array
-	inputRows := strings.Split(inputMemory, "\n")
-	updateList := make([][]string, 0)
-	seenEmptyLine := false
-	for _, row := range inputRows {
-		// Skip rows till empty line
-		if !seenEmptyLine {
-			if row == "" {
-				seenEmptyLine = true
-			}
-			continue
-		}
-		update := strings.Split(row, ",")
-		updateList = append(updateList, update)
-	}
-	return updateList
-}
-
 // contains checks if a string slice contains a specific string
 // Returns true if the string is found in the slice
 func contains(slice []string, str string) bool {
@@ -266,29 +289,48 @@ func contains(slice []string, str string) bool {
 	return false
 }
 
-// day5GetInput reads the input file and returns its contents as a string
-// Returns the input string and a boolean indicating if there was an error
-func day5GetInput() (string, bool) {
-	var inputFileNameBegins = "input" // "input" or "test"
-	file, err := os.Open(fmt.Sprintf("../inputs/%s5.txt", inputFileNameBegins))
+// day5ReadInput reads the "page1|page2" rules, a blank line and then the
+// comma separated updates. Rules are kept as they are written and updates as
+// their pages, every page being checked to be a number.
+func day5ReadInput(r io.Reader) ([]string, [][]string, error) {
+	var rules []string
+	var updates [][]string
+	section := 0
+	err := parse.EachStanza(r, func(stanza []parse.Line) error {
+		section++
+		switch section {
+		case 1:
+			rules = make([]string, 0, len(stanza))
+			for _, l := range stanza {
+				pages := parse.List(l.Field, "|")
+				if len(pages) != 2 {
+					return l.Error(`a rule "page1|page2"`)
+				}
+				for _, page := range pages {
+					if _, err := page.Int(); err != nil {
+						return input.WithLine(err, l.Number)
+					}
+				}
+				rules = append(rules, l.Text)
+			}
+		case 2:
+			updates = make([][]string, 0, len(stanza))
+			for _, l := range stanza {
+				if _, err := parse.IntList(l.Field, ","); err != nil {
+					return input.WithLine(err, l.Number)
+				}
+				updates = append(updates, strings.Split(l.Text, ","))
+			}
+		default:
+			return &input.ParseError{Line: stanza[0].Number - 1, Column: 1, Expected: "an update"}
+		}
+		return nil
+	})
 	if err != nil {
-		fmt.Println(err)
-		return "", true
+		return nil, nil, err
 	}
-	defer file.Close()
-
-	// Read all lines into memory
-	var inputMemory string
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		inputMemory += scanner.Text() + "\n"
-	}
-	// remove last \n
-	inputMemory = inputMemory[:len(inputMemory)-1]
-
-	if err := scanner.Err(); err != nil {
-		fmt.Println(err)
-		return "", true
+	if section < 2 {
+		return nil, nil, &input.ParseError{Expected: "a blank line between the rules and the updates"}
 	}
-	return inputMemory, false
+	return rules, updates, nil
 }
@@ -1,314 +1,372 @@
 package Day
 
 import (
-	"bufio"
+	"adventcode2024/anim"
+	"adventcode2024/gen"
+	"adventcode2024/input"
+	"adventcode2024/lint"
+	"adventcode2024/registry"
+	"adventcode2024/render"
+	"adventcode2024/utils/grid"
+	"adventcode2024/utils/parse"
+	"context"
 	"fmt"
-	"os"
-	"strings"
+	"image"
+	"image/color"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"slices"
 )
 
+func init() {
+	registry.Register(registry.Puzzle{Year: 2024, Day: 6, New: func() registry.Solver { return &day6Solver{} }})
+}
+
 // Day6Cell represents a single cell in the matrix
 // It tracks whether the cell is obstructed, has been visited,
 // and how many times it has been visited from each direction
 type Day6Cell struct {
-	obstructed bool // Whether the cell contains an obstacle (#)
-	visited    bool // Whether the cell has been visited at all
-	visitedN   int  // Number of times visited from North direction
-	visitedE   int  // Number of times visited from East direction
-	visitedS   int  // Number of times visited from South direction
-	visitedW   int  // Number of times visited from West direction
+	obstructed bool   // Whether the cell contains an obstacle (#)
+	visited    bool   // Whether the cell has been visited at all
+	visits     [4]int // Number of times visited moving in each of grid.Dirs4
 }
 
 // Guard represents the moving guard in the matrix
 // It tracks its position, current direction, and whether it's in a death loop
 type Guard struct {
-	row       int    // Current row position in the matrix
-	col       int    // Current column position in the matrix
-	direction string // Current direction of movement (N, E, S, W)
-	deathLoop bool   // Whether the guard is stuck in a death loop
+	pos       grid.Point // Current position in the matrix
+	direction grid.Point // Current direction of movement, one of grid.Dirs4
+	deathLoop bool       // Whether the guard is stuck in a death loop
+	turns     int        // Turns since the last move, a fourth means obstacles box the guard in
 }
 
 // Matrix represents the game board and contains the guard
-// It stores the input strings, the cell matrix, and the guard
+// It stores the cell matrix, and the guard
 type Matrix struct {
-	inputStrings []string     // Original input strings
-	cellMatrix   [][]Day6Cell // 2D matrix of cells
-	guard        *Guard       // The moving guard
+	cellMatrix *grid.Grid[Day6Cell] // Grid of cells
+	guard      *Guard               // The moving guard
+	start      grid.Point           // Position the guard starts from
+	recorder   *anim.Recorder       // Receives a frame after every move, nil for none
 }
 
-// NewMatrix creates a new Matrix from the input string
+// NewMatrix creates a new Matrix from the parsed lab map
 // It initializes the cell matrix, sets up obstacles, and finds the guard's starting position
-func NewMatrix(inputString string) *Matrix {
-	inputStrings := strings.Split(inputString, "\n")
-	cellMatrix := make([][]Day6Cell, len(inputStrings))
-	for j := range cellMatrix {
-		cellMatrix[j] = make([]Day6Cell, len(inputStrings[0]))
-		for i := range cellMatrix[j] {
-			cellMatrix[j][i] = Day6Cell{}
-			inputStr := inputStrings[j][i]
-			if inputStr == '#' {
-				cellMatrix[j][i].obstructed = true
-			}
-		}
+func NewMatrix(labMap *grid.Grid[rune]) *Matrix {
+	cellMatrix := grid.New[Day6Cell](labMap.Rows(), labMap.Cols())
+	for pos, r := range labMap.All() {
+		cellMatrix.Set(pos, Day6Cell{obstructed: r == '#'})
 	}
 
 	// Find guard position and initialize it
-	guard := &Guard{}
-	for j := range cellMatrix {
-		for i := range cellMatrix[j] {
-			if inputStrings[j][i] == '^' {
-				guard.row = j
-				guard.col = i
-				guard.direction = "N" // Guard starts facing North
-				cellMatrix[j][i].visited = true
-			}
-		}
+	guard := &Guard{direction: grid.N} // Guard starts facing North
+	for _, pos := range labMap.FindAll(func(r rune) bool { return r == '^' }) {
+		guard.pos = pos
+		cellMatrix.Ptr(pos).visited = true
 	}
 
 	return &Matrix{
-		inputStrings: inputStrings,
-		cellMatrix:   cellMatrix,
-		guard:        guard,
+		cellMatrix: cellMatrix,
+		guard:      guard,
+		start:      guard.pos,
 	}
 }
 
-// Print displays the current state of the matrix
+// String renders the current state of the matrix
 // Uses different characters to represent:
 // # - obstacles
 // ^ - guard's current position
 // x - visited cells
 // . - unvisited cells
-func (m *Matrix) Print() {
-	fmt.Print("\nMatrix:\n")
-	for j := range m.cellMatrix {
-		for i := range m.cellMatrix[j] {
-			cell := m.cellMatrix[j][i]
-			if cell.obstructed {
-				fmt.Print("#")
-			} else if m.guard.row == j && m.guard.col == i {
-				fmt.Print("^")
-			} else if cell.visited {
-				fmt.Print("x")
-			} else {
-				fmt.Print(".")
-			}
+func (m *Matrix) String() string {
+	return m.cellMatrix.Render(func(pos grid.Point, cell Day6Cell) string {
+		switch {
+		case cell.obstructed:
+			return "#"
+		case pos == m.guard.pos:
+			return "^"
+		case cell.visited:
+			return "x"
 		}
-		fmt.Println()
-	}
+		return "."
+	})
+}
+
+// day6DirName returns the compass letter of one of grid.Dirs4
+func day6DirName(dir grid.Point) string {
+	return string("NESW"[slices.Index(grid.Dirs4, dir)])
+}
+
+// emitFrame draws the matrix for an animation, what describes the step just taken
+func (m *Matrix) emitFrame(what string) {
+	m.recorder.Emit(func() anim.Frame {
+		return anim.Frame{
+			Text:  m.String(),
+			Label: fmt.Sprintf("guard at %v facing %s, %s", m.guard.pos, day6DirName(m.guard.direction), what),
+		}
+	})
 }
 
 // CellReset resets all cells' visited states
 // Used when testing different scenarios in part 2
 func (m *Matrix) CellReset() {
-	for j := range m.cellMatrix {
-		for i := range m.cellMatrix[j] {
-			m.cellMatrix[j][i].visitedW = 0
-			m.cellMatrix[j][i].visitedE = 0
-			m.cellMatrix[j][i].visitedN = 0
-			m.cellMatrix[j][i].visitedS = 0
-			m.cellMatrix[j][i].visited = false
-		}
+	for pos := range m.cellMatrix.All() {
+		cell := m.cellMatrix.Ptr(pos)
+		cell.visits = [4]int{}
+		cell.visited = false
 	}
 }
 
+// Reset puts the guard back on its starting cell facing North
+// and clears every cell's visited state
+func (m *Matrix) Reset() {
+	m.CellReset()
+	m.guard.pos = m.start
+	m.guard.direction = grid.N
+	m.guard.deathLoop = false
+	m.guard.turns = 0
+	m.cellMatrix.Ptr(m.start).visited = true
+}
+
 // MoveGuard moves the guard according to its current direction
 // Returns true if the move was valid and the guard should continue moving
 // The guard's movement rules are:
 // 1. Move in current direction if possible
-// 2. If hitting an obstacle, back up and turn right
+// 2. If hitting an obstacle, stay and turn right
 // 3. If visiting a cell too many times in same direction, enter death loop
+// 4. If turning a fourth time without moving, the guard is boxed in, also a death loop
+// Every turn and move emits a frame when the matrix is recorded.
 func (m *Matrix) MoveGuard() bool {
-	validMove := true
 	m.guard.deathLoop = false
 
 	// Check if move is within bounds
-	switch m.guard.direction {
-	case "N":
-		if m.guard.row-1 < 0 {
-			validMove = false
-		}
-	case "E":
-		if m.guard.col+1 >= len(m.cellMatrix[0]) {
-			validMove = false
-		}
-	case "S":
-		if m.guard.row+1 >= len(m.cellMatrix) {
-			validMove = false
-		}
-	case "W":
-		if m.guard.col-1 < 0 {
-			validMove = false
-		}
+	next := m.guard.pos.Add(m.guard.direction)
+	if !m.cellMatrix.In(next) {
+		return false
 	}
 
-	if validMove {
-		// Move guard
-		switch m.guard.direction {
-		case "N":
-			m.guard.row--
-		case "E":
-			m.guard.col++
-		case "S":
-			m.guard.row++
-		case "W":
-			m.guard.col--
-		}
-
-		cell := &m.cellMatrix[m.guard.row][m.guard.col]
-		if cell.obstructed {
-			// Backup and turn right
-			switch m.guard.direction {
-			case "N":
-				m.guard.row++
-				m.guard.direction = "E"
-			case "E":
-				m.guard.col--
-				m.guard.direction = "S"
-			case "S":
-				m.guard.row--
-				m.guard.direction = "W"
-			case "W":
-				m.guard.col++
-				m.guard.direction = "N"
-			}
-		} else {
-			cell.visited = true
-			switch m.guard.direction {
-			case "N":
-				cell.visitedN++
-			case "E":
-				cell.visitedE++
-			case "S":
-				cell.visitedS++
-			case "W":
-				cell.visitedW++
-			}
-
-			// Check for death loop
-			if cell.visitedN > 1 || cell.visitedE > 1 || cell.visitedS > 1 || cell.visitedW > 1 {
-				m.guard.deathLoop = true
-				validMove = false
-			}
+	cell := m.cellMatrix.Ptr(next)
+	if cell.obstructed {
+		// Stay and turn right
+		m.guard.direction = m.guard.direction.TurnRight()
+		m.guard.turns++
+		if m.guard.turns == 4 {
+			m.guard.deathLoop = true
+			m.emitFrame("boxed in")
+			return false
 		}
+		m.emitFrame("turned right")
+		return true
 	}
 
-	return validMove
+	// Move guard
+	m.guard.pos = next
+	m.guard.turns = 0
+	cell.visited = true
+	visits := &cell.visits[slices.Index(grid.Dirs4, m.guard.direction)]
+	*visits++
+
+	// Check for death loop
+	if *visits > 1 {
+		m.guard.deathLoop = true
+		m.emitFrame("stuck in a loop")
+		return false
+	}
+	m.emitFrame("moved")
+	return true
 }
 
-// Day6 solves the Advent of Code 2024 Day 6 puzzle
+// day6Solver solves the Advent of Code 2024 Day 6 puzzle
 // The puzzle involves a guard moving around a matrix and potentially getting stuck in death loops
 // Part 1: Count the number of cells visited by the guard
 // Part 2: Count the number of cells that can cause a death loop when blocked
-func Day6() {
-	fmt.Println("2024 Day6 start")
+type day6Solver struct {
+	registry.Logs
+	anim.Recorder
+	matrix *Matrix // Lab map shared by both parts, reset before each walk
+}
+
+// Parse reads the lab map and finds the guard
+func (d *day6Solver) Parse(r io.Reader) error {
+	labMap, err := grid.Read(r, grid.Runes(".#^"), `'.', '#' or the guard '^'`)
+	if err != nil {
+		return err
+	}
+	if err := day6CheckGuard(labMap); err != nil {
+		return err
+	}
+	d.matrix = NewMatrix(labMap)
+	d.matrix.recorder = &d.Recorder
+	return nil
+}
 
-	// Open file and gather raw inputs
-	inputMemory, shouldReturn := day6GetInput()
-	if shouldReturn {
-		return
+// Lint checks that the lab is a rectangle of '.', '#' and a single guard '^'
+func (d *day6Solver) Lint(r io.Reader) (lint.Problems, error) {
+	lines, err := parse.Lines(r)
+	if err != nil {
+		return nil, err
 	}
+	var problems lint.Problems
+	lint.Grid(&problems, lines, lint.Accepts(grid.Runes(".#^")), `'.', '#' or the guard '^'`)
+	lint.Markers(&problems, lines, '^', 1, 1, "a single guard '^'")
+	return problems, nil
+}
 
-	fmt.Println("inputMemory size:", len(inputMemory))
+// day6GenAttempts is how many labs Generate draws before giving up on finding
+// one the guard can leave
+const day6GenAttempts = 100
+
+// Generate writes a square lab, Size cells wide and 130 by default like the
+// real input, where Density is the share of cells holding an obstacle, 0.02 by
+// default. The guard stands on a free cell from which its walk leaves the lab,
+// labs without one are drawn again.
+func (d *day6Solver) Generate(w io.Writer, rng *rand.Rand, opts gen.Options) error {
+	opts = opts.Defaults(130, 0.02)
+	n := opts.Size
+	for range day6GenAttempts {
+		obstacles := grid.New[bool](n, n)
+		for pos := range obstacles.All() {
+			obstacles.Set(pos, gen.Chance(rng, opts.Density))
+		}
+		guard := grid.Point{Row: rng.IntN(n), Col: rng.IntN(n)}
+		if obstructed, _ := obstacles.Get(guard); obstructed || !day6Leaves(obstacles, guard) {
+			continue
+		}
 
-	// Create matrix from input
-	matrix := NewMatrix(inputMemory)
+		row := make([]byte, n+1)
+		row[n] = '\n'
+		for r := range n {
+			for c := range n {
+				pos := grid.Point{Row: r, Col: c}
+				obstructed, _ := obstacles.Get(pos)
+				switch {
+				case pos == guard:
+					row[c] = '^'
+				case obstructed:
+					row[c] = '#'
+				default:
+					row[c] = '.'
+				}
+			}
+			if _, err := w.Write(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("no lab %d wide with density %g lets the guard leave in %d attempts", n, opts.Density, day6GenAttempts)
+}
+
+// day6Leaves reports whether a guard starting at start facing North walks off the lab
+func day6Leaves(obstacles *grid.Grid[bool], start grid.Point) bool {
+	seen := grid.New[uint8](obstacles.Rows(), obstacles.Cols()) // Directions the guard left each cell in, one bit each
+	pos, dir := start, 0
+	for {
+		if *seen.Ptr(pos)&(1<<dir) != 0 {
+			return false
+		}
+		*seen.Ptr(pos) |= 1 << dir
+		next := pos.Add(grid.Dirs4[dir])
+		obstructed, in := obstacles.Get(next)
+		switch {
+		case !in:
+			return true
+		case obstructed:
+			dir = (dir + 1) % len(grid.Dirs4)
+		default:
+			pos = next
+		}
+	}
+}
 
-	// Process parts
-	day6part1(matrix)
-	matrix = NewMatrix(inputMemory) // Reset matrix for part 2
-	day6part2(matrix)
+// Part1 returns the number of cells the guard visits before leaving the map
+func (d *day6Solver) Part1(ctx context.Context) (int64, error) {
+	d.matrix.Reset()
+	return int64(day6part1(d.Log(), d.matrix)), nil
+}
 
-	fmt.Println("2024 Day6 end")
+// Part2 returns the number of cells where a new obstacle traps the guard in a loop
+func (d *day6Solver) Part2(ctx context.Context) (int64, error) {
+	d.matrix.Reset()
+	deathLoopCount, err := day6part2(ctx, d.Log(), d.matrix)
+	return int64(deathLoopCount), err
+}
+
+// Draw pictures the guard's walk of part 1: obstacles, the cells visited,
+// the start and the last cell before the guard leaves the map
+func (d *day6Solver) Draw(ctx context.Context, scale int) (image.Image, error) {
+	m := d.matrix
+	m.Reset()
+	day6part1(d.Log(), m)
+	return render.Image(m.cellMatrix, func(pos grid.Point, cell Day6Cell) color.Color {
+		switch {
+		case cell.obstructed:
+			return render.Wall
+		case pos == m.guard.pos:
+			return render.Marker
+		case pos == m.start:
+			return render.Highlight
+		case cell.visited:
+			return render.Path
+		}
+		return render.Background
+	}, scale), nil
 }
 
 // day6part1 processes part 1 of the puzzle
 // Counts the number of cells visited by the guard as it moves around
 // The guard moves until it can't move anymore or enters a death loop
-func day6part1(matrix *Matrix) {
-	// matrix.Print()
+func day6part1(log *slog.Logger, matrix *Matrix) int {
+	log.Debug("starting walk", "matrix", matrix)
 
 	for matrix.MoveGuard() {
 		// Continue moving until guard can't move anymore
 	}
 
-	// matrix.Print()
-	// fmt.Printf("Guard %d %d %s\n", matrix.guard.row, matrix.guard.col, matrix.guard.direction)
+	log.Debug("guard left the map", "pos", matrix.guard.pos, "direction", day6DirName(matrix.guard.direction), "matrix", matrix)
 
 	// Count visited cells
-	visitedCells := 0
-	for j := range matrix.cellMatrix {
-		for i := range matrix.cellMatrix[j] {
-			if matrix.cellMatrix[j][i].visited {
-				visitedCells++
-			}
-		}
-	}
-	fmt.Printf("visited count %d\n", visitedCells)
+	return len(matrix.cellMatrix.FindAll(func(cell Day6Cell) bool { return cell.visited }))
 }
 
 // day6part2 processes part 2 of the puzzle
 // For each non-obstructed cell, tests if blocking it would cause a death loop
 // A death loop occurs when the guard visits a cell too many times in the same direction
-func day6part2(matrix *Matrix) {
-	// matrix.Print()
-	guardRowStart := matrix.guard.row
-	guardColStart := matrix.guard.col
-
+// Every cell is a new simulation, so ctx is checked before each one
+func day6part2(ctx context.Context, log *slog.Logger, matrix *Matrix) (int, error) {
 	deathLoopCount := 0
-	for j := range matrix.cellMatrix {
-		for i := range matrix.cellMatrix[j] {
-			cell := &matrix.cellMatrix[j][i]
-			matrix.guard.direction = "N"
-			matrix.guard.row = guardRowStart
-			matrix.guard.col = guardColStart
-			matrix.guard.deathLoop = false
-			matrix.CellReset()
-
-			// Test if blocking this cell causes a death loop
-			if !cell.obstructed && (matrix.guard.row != j || matrix.guard.col != i) {
-				cell.obstructed = true
-				for matrix.MoveGuard() {
-					// Continue moving until guard can't move anymore
-				}
-				if matrix.guard.deathLoop {
-					deathLoopCount++
-				}
-				cell.obstructed = false
+	for pos := range matrix.cellMatrix.All() {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		cell := matrix.cellMatrix.Ptr(pos)
+		matrix.Reset()
+
+		// Test if blocking this cell causes a death loop
+		if !cell.obstructed && pos != matrix.start {
+			cell.obstructed = true
+			for matrix.MoveGuard() {
+				// Continue moving until guard can't move anymore
 			}
+			if matrix.guard.deathLoop {
+				deathLoopCount++
+				log.Debug("obstruction loops the guard", "pos", pos)
+			}
+			cell.obstructed = false
 		}
 	}
 
-	// matrix.Print()
-	fmt.Printf("deathloop count %d\n", deathLoopCount)
+	return deathLoopCount, nil
 }
 
-// day6GetInput reads the input file and returns its contents as a string
-// Returns the input string and a boolean indicating if there was an error
-// The input file should contain a matrix with:
-// # - obstacles
-// ^ - guard's starting position
-// . - empty cells
-func day6GetInput() (string, bool) {
-	var inputFileNameBegins = "input" // "input" or "test"
-	file, err := os.Open(fmt.Sprintf("../inputs/%s6.txt", inputFileNameBegins))
-	if err != nil {
-		fmt.Println(err)
-		return "", true
-	}
-	defer file.Close()
-
-	// Read all lines into memory
-	var inputMemory string
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		inputMemory += scanner.Text() + "\n"
+// day6CheckGuard checks that the lab map has exactly one guard
+func day6CheckGuard(labMap *grid.Grid[rune]) error {
+	guards := labMap.FindAll(func(r rune) bool { return r == '^' })
+	if len(guards) == 0 {
+		return &input.ParseError{Expected: "a guard '^' on the map"}
 	}
-	// remove last \n
-	inputMemory = inputMemory[:len(inputMemory)-1]
-
-	if err := scanner.Err(); err != nil {
-		fmt.Println(err)
-		return "", true
+	if len(guards) > 1 {
+		return &input.ParseError{Line: guards[1].Row + 1, Column: guards[1].Col + 1, Text: "^", Expected: "a single guard"}
 	}
-	return inputMemory, false
+	return nil
 }
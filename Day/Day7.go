@@ -1,13 +1,22 @@
 package Day
 
 import (
-	"bufio"
+	"adventcode2024/input"
+	"adventcode2024/lint"
+	"adventcode2024/registry"
+	"adventcode2024/utils/parse"
+	"context"
 	"fmt"
-	"os"
+	"io"
+	"log/slog"
+	"math"
 	"strconv"
-	"strings"
 )
 
+func init() {
+	registry.Register(registry.Puzzle{Year: 2024, Day: 7, New: func() registry.Solver { return &day7Solver{} }})
+}
+
 // Equation represents a mathematical equation with target result and input values
 // The puzzle involves finding ways to combine input values using operators to reach the target result
 type Equation struct {
@@ -19,22 +28,32 @@ type Equation struct {
 // NewEquation creates a new Equation from an input string
 // Format: "target: value1 value2 value3 ..."
 // Example: "190: 10 19" means target is 190, input values are [10, 19]
-func NewEquation(inputString string) *Equation {
-	splitString := strings.Split(inputString, ": ")
-	targetResult, _ := strconv.ParseInt(splitString[0], 10, 64)
+// A malformed string gives a *input.ParseError without a line number
+func NewEquation(inputString string) (*Equation, error) {
+	const expected = `"target: value1 value2 ..."`
+	target, values, ok := parse.Cut(input.Line(inputString), ": ")
+	if !ok {
+		return nil, input.Line(inputString).Error(expected)
+	}
+	targetResult, err := target.Int64()
+	if err != nil {
+		return nil, err
+	}
 
 	// Parse input values from space-separated string
-	inputValues := make([]int, 0)
-	for _, input := range strings.Split(splitString[1], " ") {
-		value, _ := strconv.Atoi(input)
-		inputValues = append(inputValues, value)
+	inputValues, err := parse.IntList(values, "")
+	if err != nil {
+		return nil, err
+	}
+	if len(inputValues) == 0 {
+		return nil, input.Line(inputString).Error(expected)
 	}
 
 	return &Equation{
 		TargetResult:    targetResult,
 		InputValues:     inputValues,
 		PossibleResults: make([]int64, 0),
-	}
+	}, nil
 }
 
 // calcNextInput recursively calculates all possible results using different operators
@@ -78,59 +97,91 @@ func calcNextInput(inputList []int, index int, result int64, operator string, al
 	}
 }
 
-// Day7 solves the Advent of Code 2024 Day 7 puzzle
+// day7Solver solves the Advent of Code 2024 Day 7 puzzle
 // The puzzle involves finding equations where the target result can be achieved
 // using different combinations of operators on the input values
 //
 // Part 1: Use addition (+) and multiplication (*) operators
 // Part 2: Also use concatenation (|) operator
-func Day7() {
-	fmt.Println("2024 Day7 start")
+type day7Solver struct {
+	registry.Logs
+	equations []*Equation // Calibration equations shared by both parts
+}
 
-	// Open file and gather raw inputs
-	inputMemory, shouldReturn := day7GetInput()
-	if shouldReturn {
-		return
+// Parse reads one equation per line
+func (d *day7Solver) Parse(r io.Reader) error {
+	// Parse input into equations
+	d.equations = make([]*Equation, 0)
+	err := parse.Each(r, func(l parse.Line) error {
+		equation, err := NewEquation(l.Text)
+		if err != nil {
+			return input.WithLine(err, l.Number)
+		}
+		d.equations = append(d.equations, equation)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, equation := range d.equations {
+		d.Log().Debug("equation", "target", equation.TargetResult, "inputs", equation.InputValues)
 	}
 
-	fmt.Println("inputMemory size:", len(inputMemory))
+	return nil
+}
+
+// Lint checks that every line is a target of 0 or more followed by at least one value of 0 or more
+func (d *day7Solver) Lint(r io.Reader) (lint.Problems, error) {
+	lines, err := parse.Lines(r)
+	if err != nil {
+		return nil, err
+	}
+	const expected = `"target: value1 value2 ..."`
+	var problems lint.Problems
+	lint.NotEmpty(&problems, lines, expected)
+	for _, l := range lines {
+		target, values, ok := parse.Cut(l.Field, ": ")
+		if !ok || len(parse.List(values, "")) == 0 {
+			problems.Add(l.Number, l.Field, expected)
+			continue
+		}
+		lint.Int(&problems, l.Number, target, 0, math.MaxInt)
+		for _, value := range parse.List(values, "") {
+			lint.Int(&problems, l.Number, value, 0, math.MaxInt)
+		}
+	}
+	return problems, nil
+}
 
-	// Process parts
-	day7part1(inputMemory, false) // Part 1: Only + and * operators
-	day7part2(inputMemory)        // Part 2: Also includes | operator
+// Part1 returns the total calibration result using + and *
+func (d *day7Solver) Part1(ctx context.Context) (int64, error) {
+	return day7part1(ctx, d.Log(), d.equations, false)
+}
 
-	fmt.Println("2024 Day7 end")
+// Part2 returns the total calibration result using +, * and concatenation
+func (d *day7Solver) Part2(ctx context.Context) (int64, error) {
+	return day7part2(ctx, d.Log(), d.equations)
 }
 
 // day7part1 processes part 1 of the puzzle
 // Finds equations where the target result can be achieved using the input values
 // Parameters:
-//   - inputMemory: Raw input string containing equations
+//   - log: Logger for the equations that reach their target
+//   - equations: Parsed equations
 //   - isPart2: Whether to include concatenation operator (|)
-func day7part1(inputMemory string, isPart2 bool) {
-	// Parse input into equations
-	equations := make([]*Equation, 0)
-	inputStrings := strings.Split(inputMemory, "\n")
-	for _, inputString := range inputStrings {
-		equation := NewEquation(inputString)
-		equations = append(equations, equation)
-	}
-
-	// Print equations for debugging
-	// for _, equation := range equations {
-	// 	fmt.Printf("Equation %d %v\n", equation.TargetResult, equation.InputValues)
-	// }
-	// fmt.Println()
-
+//
+// Returns the sum of the target results that can be achieved,
+// or ctx's error if ctx is cancelled between two equations
+func day7part1(ctx context.Context, log *slog.Logger, equations []*Equation, isPart2 bool) (int64, error) {
 	// Calculate possible results for each equation
 	for _, equation := range equations {
-		allResults := make([]int64, 0)
-		// Try each operator starting with first value
-		calcNextInput(equation.InputValues, 1, int64(equation.InputValues[0]), "+", &allResults, isPart2)
-		calcNextInput(equation.InputValues, 1, int64(equation.InputValues[0]), "*", &allResults, isPart2)
-		if isPart2 {
-			calcNextInput(equation.InputValues, 1, int64(equation.InputValues[0]), "|", &allResults, isPart2)
+		if err := ctx.Err(); err != nil {
+			return 0, err
 		}
+		allResults := make([]int64, 0)
+		// Start from 0 + the first value, so a single value is a result of its own
+		calcNextInput(equation.InputValues, 0, 0, "+", &allResults, isPart2)
 		equation.PossibleResults = allResults
 	}
 
@@ -140,55 +191,18 @@ func day7part1(inputMemory string, isPart2 bool) {
 		// Check if target result is in possible results
 		for _, result := range equation.PossibleResults {
 			if result == equation.TargetResult {
-				//fmt.Printf("success Equation %d %v %v\n", equation.TargetResult, equation.InputValues, equation.PossibleResults)
+				log.Debug("success", "target", equation.TargetResult, "inputs", equation.InputValues, "possibleResults", len(equation.PossibleResults))
 				sumSuccessTargets += equation.TargetResult
 				break
 			}
 		}
 	}
-	if isPart2 {
-		fmt.Printf("\nsumSuccessTargets part2: %d\n\n", sumSuccessTargets)
-	} else {
-		fmt.Printf("\nsumSuccessTargets part1: %d\n\n", sumSuccessTargets)
-	}
+	return sumSuccessTargets, nil
 }
 
 // day7part2 processes part 2 of the puzzle
 // Uses the same logic as part 1 but includes the concatenation operator (|)
-func day7part2(inputMemory string) {
+func day7part2(ctx context.Context, log *slog.Logger, equations []*Equation) (int64, error) {
 	// call part1 with isPart2 = true
-	day7part1(inputMemory, true)
-}
-
-// day7GetInput reads the input file and returns its contents as a string
-// Returns the input string and a boolean indicating if there was an error
-//
-// The input file should contain equations in the format:
-// target: value1 value2 value3 ...
-// Example:
-// 190: 10 19
-// 3267: 81 40 27
-func day7GetInput() (string, bool) {
-	var inputFileNameBegins = "input" // "input" or "test"
-	file, err := os.Open(fmt.Sprintf("../inputs/%s7.txt", inputFileNameBegins))
-	if err != nil {
-		fmt.Println(err)
-		return "", true
-	}
-	defer file.Close()
-
-	// Read all lines into memory
-	var inputMemory string
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		inputMemory += scanner.Text() + "\n"
-	}
-	// remove last \n
-	inputMemory = inputMemory[:len(inputMemory)-1]
-
-	if err := scanner.Err(); err != nil {
-		fmt.Println(err)
-		return "", true
-	}
-	return inputMemory, false
+	return day7part1(ctx, log, equations, true)
 }
@@ -6,63 +6,63 @@
 package Day
 
 import (
-	"bufio"
-	"fmt"
-	"os"
-	"strings"
+	"adventcode2024/lint"
+	"adventcode2024/registry"
+	"adventcode2024/render"
+	"adventcode2024/utils/grid"
+	"adventcode2024/utils/parse"
+	"context"
+	"image"
+	"image/color"
+	"io"
+	"log/slog"
+	"unicode"
 )
 
+func init() {
+	registry.Register(registry.Puzzle{Year: 2024, Day: 8, New: func() registry.Solver { return &day8Solver{} }})
+}
+
 // Day8Cell represents a single cell in the antenna matrix.
 // Each cell can contain an antenna with a specific frequency and tracks interference points (anti-nodes)
 // from other antennas with matching frequencies.
 type Day8Cell struct {
-	antennaFrequency string   // The frequency of the antenna (if present)
-	antiNodeList     []string // List of frequencies that create interference points in this cell
-	brotherList      [][]int  // List of coordinates [row,col] for other antennas with matching frequency
+	antennaFrequency string       // The frequency of the antenna (if present)
+	antiNodeList     []string     // List of frequencies that create interference points in this cell
+	brotherList      []grid.Point // Positions of the other antennas with matching frequency
 }
 
-// Day8Matrix represents the game board containing cells with antennas and their interference patterns.
-// The matrix tracks both the original input and the processed cell data for calculating interference patterns.
+// Day8Matrix represents the complete antenna grid.
+// The matrix holds the cell data for calculating interference patterns.
 type Day8Matrix struct {
-	inputStrings []string      // Original input strings representing the raw matrix
-	cellMatrix   [][]*Day8Cell // 2D matrix of cells containing antenna and interference data
+	cellMatrix *grid.Grid[*Day8Cell] // Grid of cells containing antenna and interference data
 }
 
-// day8NewMatrix creates a new Matrix from the input string.
-// It parses the input string into a 2D matrix, initializing cells with antennas
-// where specified in the input. Empty cells are represented by '.' in the input.
+// day8NewCell is the grid mapper for the antenna map.
+// Empty cells are represented by '.' in the input, letters and digits are antenna frequencies.
 //
 // Parameters:
-//   - inputString: Raw string containing the matrix layout, with '.' for empty cells
-//     and other characters representing antenna frequencies
+//   - r: Rune of the cell in the input
 //
 // Returns:
-//   - *Day8Matrix: Initialized matrix with antenna positions and empty cells
-func day8NewMatrix(inputString string) *Day8Matrix {
-	inputStrings := strings.Split(inputString, "\n")
-	cellMatrix := make([][]*Day8Cell, len(inputStrings))
-	for j := range cellMatrix {
-		cellMatrix[j] = make([]*Day8Cell, len(inputStrings[0]))
-		for i := range cellMatrix[j] {
-			cell := &Day8Cell{
-				antiNodeList: make([]string, 0),
-			}
-			inputStr := inputStrings[j][i]
-			if inputStr != '.' {
-				cell.antennaFrequency = string(inputStr)
-				cell.antiNodeList = append(cell.antiNodeList, string(inputStr))
-			}
-			cellMatrix[j][i] = cell
-		}
+//   - *Day8Cell: Initialized cell, with its antenna if it has one
+//   - bool: false for a rune that is neither '.' nor a frequency
+func day8NewCell(r rune) (*Day8Cell, bool) {
+	cell := &Day8Cell{
+		antiNodeList: make([]string, 0),
 	}
-
-	return &Day8Matrix{
-		inputStrings: inputStrings,
-		cellMatrix:   cellMatrix,
+	switch {
+	case r == '.':
+	case unicode.IsLetter(r) || unicode.IsDigit(r):
+		cell.antennaFrequency = string(r)
+		cell.antiNodeList = append(cell.antiNodeList, string(r))
+	default:
+		return nil, false
 	}
+	return cell, true
 }
 
-// Print displays the current state of the matrix to standard output.
+// String renders the current state of the matrix.
 // The display uses the following format:
 //   - '.' for empty cells
 //   - '#' for cells containing interference points (anti-nodes)
@@ -70,21 +70,15 @@ func day8NewMatrix(inputString string) *Day8Matrix {
 //
 // This method is useful for visualizing the matrix state during processing
 // and debugging the interference pattern calculations.
-func (m *Day8Matrix) Print() {
-	fmt.Print("\nMatrix:\n")
-	for _, matrixRow := range m.cellMatrix {
-		for _, cell := range matrixRow {
-			if cell.antennaFrequency != "" {
-				fmt.Print(cell.antennaFrequency)
-			} else if len(cell.antiNodeList) > 0 {
-				fmt.Print("#")
-			} else {
-				fmt.Print(".")
-			}
+func (m *Day8Matrix) String() string {
+	return m.cellMatrix.Render(func(_ grid.Point, cell *Day8Cell) string {
+		if cell.antennaFrequency != "" {
+			return cell.antennaFrequency
+		} else if len(cell.antiNodeList) > 0 {
+			return "#"
 		}
-		fmt.Println()
-	}
-	fmt.Println()
+		return "."
+	})
 }
 
 // getBrotherList finds all cells containing antennas with the same frequency.
@@ -93,18 +87,15 @@ func (m *Day8Matrix) Print() {
 //
 // Parameters:
 //   - antennaFrequency: The frequency to search for in the matrix
-//   - row: Current antenna's row position to exclude from results
-//   - col: Current antenna's column position to exclude from results
+//   - pos: Current antenna's position to exclude from results
 //
 // Returns:
-//   - [][]int: List of [row,col] coordinates for matching antennas, excluding the input position
-func (m *Day8Matrix) getBrotherList(antennaFrequency string, row, col int) [][]int {
-	brotherList := make([][]int, 0)
-	for j := range m.cellMatrix {
-		for i := range m.cellMatrix[j] {
-			if m.cellMatrix[j][i].antennaFrequency == antennaFrequency && (j != row || i != col) {
-				brotherList = append(brotherList, []int{j, i})
-			}
+//   - []grid.Point: Positions of matching antennas, excluding the input position
+func (m *Day8Matrix) getBrotherList(antennaFrequency string, pos grid.Point) []grid.Point {
+	brotherList := make([]grid.Point, 0)
+	for _, brother := range m.cellMatrix.FindAll(func(cell *Day8Cell) bool { return cell.antennaFrequency == antennaFrequency }) {
+		if brother != pos {
+			brotherList = append(brotherList, brother)
 		}
 	}
 	return brotherList
@@ -116,47 +107,38 @@ func (m *Day8Matrix) getBrotherList(antennaFrequency string, row, col int) [][]i
 //
 // Parameters:
 //   - wave: The wave number determining distance from the antenna line
-//   - brother: Coordinates [row,col] of the matching antenna
-//   - row: Row coordinate of the current antenna
-//   - col: Column coordinate of the current antenna
+//   - brother: Position of the matching antenna
+//   - pos: Position of the current antenna
 //   - cell: The current antenna cell being processed
 //
 // Returns:
 //   - bool: true if valid anti-nodes were found and added, false if no valid points were found
-func (m *Day8Matrix) calcAntiNodesByWave(wave int, brother []int, row, col int, cell *Day8Cell) bool {
-	brotherRow := brother[0]
-	brotherCol := brother[1]
-
-	// Calculate anti-nodes in both directions
-	fromAntiNodeRow := row - ((brotherRow - row) * wave)
-	fromAntiNodeCol := col - ((brotherCol - col) * wave)
+func (m *Day8Matrix) calcAntiNodesByWave(wave int, brother, pos grid.Point, cell *Day8Cell) bool {
+	step := brother.Sub(pos).Mul(wave)
 	foundNodes := false
 
-	// Check and add anti-node in first direction
-	if fromAntiNodeRow >= 0 && fromAntiNodeCol >= 0 &&
-		fromAntiNodeRow < len(m.cellMatrix) && fromAntiNodeCol < len(m.cellMatrix[0]) {
-		m.cellMatrix[fromAntiNodeRow][fromAntiNodeCol].antiNodeList = append(
-			m.cellMatrix[fromAntiNodeRow][fromAntiNodeCol].antiNodeList,
-			cell.antennaFrequency,
-		)
-		foundNodes = true
-	}
-
-	// Calculate and add anti-node in second direction
-	intoAntiNodeRow := brotherRow + ((brotherRow - row) * wave)
-	intoAntiNodeCol := brotherCol + ((brotherCol - col) * wave)
-	if intoAntiNodeRow >= 0 && intoAntiNodeCol >= 0 &&
-		intoAntiNodeRow < len(m.cellMatrix) && intoAntiNodeCol < len(m.cellMatrix[0]) {
-		m.cellMatrix[intoAntiNodeRow][intoAntiNodeCol].antiNodeList = append(
-			m.cellMatrix[intoAntiNodeRow][intoAntiNodeCol].antiNodeList,
-			cell.antennaFrequency,
-		)
-		foundNodes = true
+	// Check and add anti-node in both directions, away from the antenna and beyond the brother
+	for _, antiNode := range []grid.Point{pos.Sub(step), brother.Add(step)} {
+		if antiNodeCell, ok := m.cellMatrix.Get(antiNode); ok {
+			antiNodeCell.antiNodeList = append(antiNodeCell.antiNodeList, cell.antennaFrequency)
+			foundNodes = true
+		}
 	}
 
 	return foundNodes
 }
 
+// resetAntiNodes clears every interference point so calcAntiNodes can run again.
+// Cells with an antenna keep their own frequency as an anti-node.
+func (m *Day8Matrix) resetAntiNodes() {
+	for _, cell := range m.cellMatrix.All() {
+		cell.antiNodeList = cell.antiNodeList[:0]
+		if cell.antennaFrequency != "" {
+			cell.antiNodeList = append(cell.antiNodeList, cell.antennaFrequency)
+		}
+	}
+}
+
 // calcAntiNodes processes the entire matrix to find all interference points.
 // For each antenna in the matrix, this method:
 // 1. Finds all other antennas with matching frequency (brothers)
@@ -164,107 +146,100 @@ func (m *Day8Matrix) calcAntiNodesByWave(wave int, brother []int, row, col int,
 // 3. Updates the antiNodeList for each cell where interference occurs
 //
 // This is the main processing function that identifies all interference patterns in the matrix.
-func (m *Day8Matrix) calcAntiNodes() {
-	fmt.Println("CalcAntiNodes")
-	for row := range m.cellMatrix {
-		for col := range m.cellMatrix[row] {
-			cell := m.cellMatrix[row][col]
-			if cell.antennaFrequency != "" {
-				// Cell has an antenna, find its brothers
-				// fmt.Printf("\n%d,%d antennaFreq %s", row, col, cell.antennaFrequency)
-				cell.brotherList = m.getBrotherList(cell.antennaFrequency, row, col)
-				// fmt.Printf(" brothers: %d", len(cell.brotherList))
-
-				// Calculate anti-nodes for each brother
-				for _, brother := range cell.brotherList {
-					wave := 1
-					for m.calcAntiNodesByWave(wave, brother, row, col, cell) {
-						wave++
-					}
+func (m *Day8Matrix) calcAntiNodes(log *slog.Logger) {
+	for pos, cell := range m.cellMatrix.All() {
+		if cell.antennaFrequency != "" {
+			// Cell has an antenna, find its brothers
+			cell.brotherList = m.getBrotherList(cell.antennaFrequency, pos)
+			log.Debug("antenna", "pos", pos, "antennaFreq", cell.antennaFrequency, "brothers", len(cell.brotherList))
+
+			// Calculate anti-nodes for each brother
+			for _, brother := range cell.brotherList {
+				wave := 1
+				for m.calcAntiNodesByWave(wave, brother, pos, cell) {
+					wave++
 				}
 			}
 		}
 	}
 }
 
-// Day8 solves the Advent of Code 2024 Day 8 puzzle.
+// day8Solver solves the Advent of Code 2024 Day 8 puzzle.
 // The puzzle involves finding interference patterns in a matrix of antennas.
 // Each antenna broadcasts at a specific frequency, and interference points (anti-nodes)
 // occur at specific positions relative to pairs of antennas with matching frequencies.
 //
 // The solution follows these steps:
-// 1. Read the input matrix from file
+// 1. Read the input matrix
 // 2. Create a matrix structure with antenna positions
 // 3. Calculate all interference points
 // 4. Count the total number of cells containing interference points
 //
-// The final answer is the count of cells that contain at least one interference point.
-func Day8() {
-	fmt.Println("2024 Day8 start")
+// Only part 2, where anti-nodes repeat at every wave along the antenna line, is solved.
+type day8Solver struct {
+	registry.Logs
+	matrix *Day8Matrix // Antenna map shared by both parts
+}
 
-	// Open file and gather raw inputs
-	inputMemory, shouldReturn := day8GetInput()
-	if shouldReturn {
-		return
+// Parse reads the antenna map
+func (d *day8Solver) Parse(r io.Reader) error {
+	cellMatrix, err := grid.Read(r, day8NewCell, "'.' or an antenna letter or digit")
+	if err != nil {
+		return err
 	}
+	d.matrix = &Day8Matrix{cellMatrix: cellMatrix}
+	return nil
+}
 
-	fmt.Println("inputMemory size:", len(inputMemory))
-
-	// Create matrix from input
-	matrix := day8NewMatrix(inputMemory)
-	matrix.calcAntiNodes()
-
-	// matrix.Print()
-
-	// Count cells with anti-nodes
-	countAntiNodes := 0
-	for row := range matrix.cellMatrix {
-		for col := range matrix.cellMatrix[row] {
-			cell := matrix.cellMatrix[row][col]
-			if len(cell.antiNodeList) > 0 {
-				countAntiNodes++
-				// fmt.Printf(" %d,%d %s antiNodes: %v\n",
-				// 	row, col, cell.antennaFrequency, cell.antiNodeList)
-			}
-		}
+// Lint checks that the map is a rectangle of '.' and antennas named by an ASCII letter or digit
+func (d *day8Solver) Lint(r io.Reader) (lint.Problems, error) {
+	lines, err := parse.Lines(r)
+	if err != nil {
+		return nil, err
+	}
+	antenna := func(r rune) bool {
+		return r == '.' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9'
 	}
-	fmt.Printf("countAntiNodes: %d\n", countAntiNodes)
+	var problems lint.Problems
+	lint.Grid(&problems, lines, antenna, "'.' or an antenna letter or digit")
+	return problems, nil
+}
 
-	fmt.Println("2024 Day8 end")
+// Part1 is not solved yet
+func (d *day8Solver) Part1(ctx context.Context) (int64, error) {
+	return 0, registry.ErrNotImplemented
 }
 
-// day8GetInput reads the puzzle input file and returns its contents.
-// The input file should contain a matrix where:
-//   - '.' represents empty cells
-//   - Any other character represents an antenna with that frequency
-//
-// Returns:
-//   - string: The contents of the input file
-//   - bool: true if an error occurred, false otherwise
-//
-// The function handles file operations and error checking, returning appropriate values
-// to indicate success or failure of the input reading process.
-func day8GetInput() (string, bool) {
-	var inputFileNameBegins = "input" // "input" or "test"
-	file, err := os.Open(fmt.Sprintf("../inputs/%s8.txt", inputFileNameBegins))
-	if err != nil {
-		fmt.Println(err)
-		return "", true
-	}
-	defer file.Close()
+// Draw pictures the antennas in a colour per frequency and highlights
+// the interference points of part 2 that are not on an antenna
+func (d *day8Solver) Draw(ctx context.Context, scale int) (image.Image, error) {
+	d.matrix.resetAntiNodes()
+	d.matrix.calcAntiNodes(d.Log())
+	return render.Image(d.matrix.cellMatrix, func(_ grid.Point, cell *Day8Cell) color.Color {
+		switch {
+		case cell.antennaFrequency != "":
+			return render.Distinct(int(cell.antennaFrequency[0]))
+		case len(cell.antiNodeList) > 0:
+			return render.Highlight
+		}
+		return render.Background
+	}, scale), nil
+}
 
-	// Read all lines into memory
-	var inputMemory string
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		inputMemory += scanner.Text() + "\n"
-	}
-	// remove last \n
-	inputMemory = inputMemory[:len(inputMemory)-1]
+// Part2 returns the number of cells containing at least one interference point
+func (d *day8Solver) Part2(ctx context.Context) (int64, error) {
+	matrix := d.matrix
+	matrix.resetAntiNodes()
+	matrix.calcAntiNodes(d.Log())
+	d.Log().Debug("anti-nodes", "matrix", matrix)
 
-	if err := scanner.Err(); err != nil {
-		fmt.Println(err)
-		return "", true
+	// Count cells with anti-nodes
+	countAntiNodes := 0
+	for pos, cell := range matrix.cellMatrix.All() {
+		if len(cell.antiNodeList) > 0 {
+			countAntiNodes++
+			d.Log().Debug("anti-node cell", "pos", pos, "antennaFreq", cell.antennaFrequency, "antiNodes", cell.antiNodeList)
+		}
 	}
-	return inputMemory, false
+	return int64(countAntiNodes), nil
 }
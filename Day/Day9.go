@@ -7,18 +7,30 @@
 package Day
 
 import (
-	"bufio"
-	"fmt"
-	"os"
+	"adventcode2024/anim"
+	"adventcode2024/gen"
+	"adventcode2024/input"
+	"adventcode2024/lint"
+	"adventcode2024/registry"
+	"adventcode2024/utils/parse"
+	"context"
+	"io"
+	"math/rand/v2"
 	"strconv"
+	"strings"
 )
 
+func init() {
+	registry.Register(registry.Puzzle{Year: 2024, Day: 9, New: func() registry.Solver { return &day9Solver{} }})
+}
+
 // DiskMap represents the disk storage with file positions and empty spaces.
 // The disk is represented as a linear array where:
 //   - Positive integers represent file IDs
 //   - -1 represents empty space
 type DiskMap struct {
-	Map []int // Slice representing disk positions, -1 for empty space, fileId for files
+	Map      []int          // Slice representing disk positions, -1 for empty space, fileId for files
+	recorder *anim.Recorder // Receives a frame after every file move, nil for none
 }
 
 // day9NewDiskMap creates a new DiskMap from the input string.
@@ -63,23 +75,25 @@ func day9NewDiskMap(inputString string) *DiskMap {
 	return diskMap
 }
 
-// Print displays the current state of the disk map to standard output.
+// String renders the current state of the disk map.
 // The display format is:
 //   - "." for empty spaces
 //   - File ID number for occupied spaces
 //
 // This method is useful for visualizing the disk state during defragmentation
-// and debugging the file movement process.
-func (d *DiskMap) Print() {
+// and debugging the file movement process. Logging the disk map renders it
+// only when the record is written.
+func (d *DiskMap) String() string {
+	var sb strings.Builder
 	for i := 0; i < len(d.Map); i++ {
 		output := d.Map[i]
 		if output == -1 {
-			fmt.Print(". ")
+			sb.WriteString(". ")
 		} else {
-			fmt.Printf("%d ", output)
+			sb.WriteString(strconv.Itoa(output) + " ")
 		}
 	}
-	fmt.Println()
+	return sb.String()
 }
 
 // getLastFileID returns the highest fileID present in the disk map.
@@ -182,6 +196,8 @@ func (d *DiskMap) getFirstEmptyPositionByLength(fileLength int) int {
 // 3. Finds the earliest empty space that can fit the entire file
 // 4. Moves the file if a better position is found
 //
+// Every move emits a frame when the disk map is recorded.
+//
 // Returns:
 //   - bool: true if any files were moved during this pass, false otherwise
 func (d *DiskMap) DefragmentWholeFilesOnce() bool {
@@ -200,6 +216,9 @@ func (d *DiskMap) DefragmentWholeFilesOnce() bool {
 				d.Map[positionFileCtr+i] = -1
 			}
 			moved = true
+			d.recorder.Emit(func() anim.Frame {
+				return anim.Frame{Text: d.String(), Label: "moved file " + strconv.Itoa(fileIDCtr)}
+			})
 		}
 	}
 	return moved
@@ -222,7 +241,7 @@ func (d *DiskMap) CalculateChecksum() string {
 	return strconv.FormatInt(checksum, 10)
 }
 
-// Day9 solves the Advent of Code 2024 Day 9 puzzle.
+// day9Solver solves the Advent of Code 2024 Day 9 puzzle.
 // The puzzle involves optimizing file storage on a disk by moving files
 // to reduce fragmentation. The solution follows these steps:
 // 1. Read the input describing initial file and space positions
@@ -230,67 +249,105 @@ func (d *DiskMap) CalculateChecksum() string {
 // 3. Perform defragmentation by moving files towards the start
 // 4. Calculate a checksum based on final file positions
 //
-// The final answer is the checksum value after defragmentation.
-func Day9() {
-	fmt.Println("2024 Day9 start")
+// Only part 2, which moves whole files, is solved.
+type day9Solver struct {
+	registry.Logs
+	anim.Recorder
+	diskMap *DiskMap // Disk layout as read from the input, never defragmented
+}
 
-	// Open file and gather raw inputs
-	inputMemory, shouldReturn := day9GetInput()
-	if shouldReturn {
-		return
+// Parse reads the dense disk map
+func (d *day9Solver) Parse(r io.Reader) error {
+	inputMemory, err := day9GetInput(r)
+	if err != nil {
+		return err
 	}
+	d.diskMap = day9NewDiskMap(inputMemory)
+	return nil
+}
 
-	fmt.Printf("inputMemory size: %d\n", len(inputMemory))
+// Lint checks that the disk map is a single line of digits
+func (d *day9Solver) Lint(r io.Reader) (lint.Problems, error) {
+	lines, err := parse.Lines(r)
+	if err != nil {
+		return nil, err
+	}
+	var problems lint.Problems
+	line, ok := lint.SingleLine(&problems, lines, "a disk map of digits")
+	if !ok {
+		return problems, nil
+	}
+	for col, ch := range line.Text {
+		if ch < '0' || ch > '9' {
+			problems.Add(line.Number, input.Field{Text: string(ch), Column: col + 1}, "a digit")
+		}
+	}
+	return problems, nil
+}
 
-	// Create disk map from input
-	diskMap := day9NewDiskMap(inputMemory)
+// Generate writes a disk map of Size files, 10000 by default like the real
+// input. Files are 1 to 9 blocks long. Density is the share of files followed
+// by free space, 0.9 by default, the free space being 1 to 9 blocks long.
+func (d *day9Solver) Generate(w io.Writer, rng *rand.Rand, opts gen.Options) error {
+	opts = opts.Defaults(10000, 0.9)
+	digits := make([]byte, 0, 2*opts.Size)
+	for id := range opts.Size {
+		if id > 0 {
+			free := 0
+			if gen.Chance(rng, opts.Density) {
+				free = gen.Between(rng, 1, 9)
+			}
+			digits = append(digits, byte('0'+free))
+		}
+		digits = append(digits, byte('0'+gen.Between(rng, 1, 9)))
+	}
+	_, err := w.Write(append(digits, '\n'))
+	return err
+}
+
+// Part1 is not solved yet
+func (d *day9Solver) Part1(ctx context.Context) (int64, error) {
+	return 0, registry.ErrNotImplemented
+}
 
-	// Print initial disk map
-	diskMap.Print()
+// Part2 returns the checksum after moving whole files towards the start
+func (d *day9Solver) Part2(ctx context.Context) (int64, error) {
+	// Defragment a copy so the parsed layout stays intact
+	diskMap := &DiskMap{Map: append([]int(nil), d.diskMap.Map...), recorder: &d.Recorder}
+	d.Emit(func() anim.Frame { return anim.Frame{Text: diskMap.String(), Label: "initial disk map"} })
+
+	d.Log().Debug("disk map", "diskMap", diskMap)
 
 	// Defragment disk map
 	diskMap.DefragmentWholeFilesOnce()
 
-	// Print defragmented disk map
-	fmt.Println("defragmented disk map")
-	diskMap.Print()
+	d.Log().Debug("defragmented disk map", "diskMap", diskMap)
 
 	// Calculate checksum
-	checksum := diskMap.CalculateChecksum()
-	fmt.Printf("checksum: %s\n", checksum)
-
-	fmt.Println("2024 Day9 end")
+	return strconv.ParseInt(diskMap.CalculateChecksum(), 10, 64)
 }
 
-// day9GetInput reads the puzzle input file and returns its contents.
-// The input file contains a string of digits where:
+// day9GetInput reads the puzzle input and returns its contents.
+// The input contains a string of digits where:
 //   - Even-indexed digits represent file sizes
 //   - Odd-indexed digits represent empty space sizes
 //
 // Returns:
-//   - string: The contents of the input file
-//   - bool: true if an error occurred, false otherwise
-//
-// The function handles file operations and error checking, returning appropriate values
-// to indicate success or failure of the input reading process.
-func day9GetInput() (string, bool) {
-	var inputFileNameBegins = "input" // "input" or "test"
-	file, err := os.Open(fmt.Sprintf("../inputs/%s9.txt", inputFileNameBegins))
+//   - string: The contents of the input
+//   - error: Any error reading the input, or a *input.ParseError for a non-digit
+func day9GetInput(r io.Reader) (string, error) {
+	var inputMemory strings.Builder
+	err := parse.Each(r, func(l parse.Line) error {
+		for col, ch := range l.Text {
+			if ch < '0' || ch > '9' {
+				return &input.ParseError{Line: l.Number, Column: col + 1, Text: string(ch), Expected: "a digit"}
+			}
+		}
+		inputMemory.WriteString(l.Text)
+		return nil
+	})
 	if err != nil {
-		fmt.Println(err)
-		return "", true
-	}
-	defer file.Close()
-
-	scanner := bufio.NewScanner(file)
-	var inputMemory string
-	for scanner.Scan() {
-		inputMemory += scanner.Text()
-	}
-
-	if err := scanner.Err(); err != nil {
-		fmt.Println(err)
-		return "", true
+		return "", err
 	}
-	return inputMemory, false
+	return inputMemory.String(), nil
 }
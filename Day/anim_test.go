@@ -0,0 +1,58 @@
+package Day
+
+import (
+	"adventcode2024/anim"
+	"adventcode2024/input"
+	"adventcode2024/registry"
+	"strings"
+	"testing"
+)
+
+// TestAnimations checks that the simulation days emit frames of the whole state
+// and that recording them does not change the answer
+func TestAnimations(t *testing.T) {
+	tests := []struct {
+		day, part int
+		rows      int   // Lines in every frame
+		want      int64 // Answer with frames recorded
+		lastLabel string
+		minFrames int
+	}{
+		{day: 6, part: 1, rows: 10, want: 41, lastLabel: "guard at 9,7 facing S, moved", minFrames: 41},
+		{day: 9, part: 2, rows: 1, want: 2858, lastLabel: "moved file 2", minFrames: 4},
+		{day: 14, part: 1, rows: 7, want: 12, lastLabel: "second 100", minFrames: 100},
+	}
+	for _, tt := range tests {
+		p, _ := registry.Lookup(2024, tt.day)
+		solver := p.New()
+		registry.SetExample(solver, true)
+		file, err := testLoader.Open(tt.day, input.Variant{Kind: input.Example, Example: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = solver.Parse(file)
+		file.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var frames []anim.Frame
+		solver.(anim.Animated).SetFrameHook(func(f anim.Frame) { frames = append(frames, f) })
+		got, err := registry.Solve(t.Context(), solver, tt.part)
+		if err != nil || got != tt.want {
+			t.Errorf("day %d part %d = %d, %v, want %d", tt.day, tt.part, got, err, tt.want)
+		}
+		if len(frames) < tt.minFrames {
+			t.Fatalf("day %d emitted %d frames, want at least %d", tt.day, len(frames), tt.minFrames)
+		}
+		for i, f := range frames {
+			if rows := strings.Count(strings.TrimSuffix(f.Text, "\n"), "\n") + 1; rows != tt.rows {
+				t.Errorf("day %d frame %d has %d rows, want %d", tt.day, i+1, rows, tt.rows)
+				break
+			}
+		}
+		if last := frames[len(frames)-1].Label; last != tt.lastLabel {
+			t.Errorf("day %d last frame %q, want %q", tt.day, last, tt.lastLabel)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package Day
+
+import (
+	"adventcode2024/gen"
+	"adventcode2024/registry"
+	"bytes"
+	"cmp"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	diffInputs = flag.Int("diff.inputs", 10, "generated inputs of each size per day in TestDifferential")
+	diffSize   = flag.Int("diff.size", 0, "largest size generated in TestDifferential, 0 for each day's own")
+)
+
+// diffTimeout bounds one solve, a solver that runs longer disagrees with its reference
+const diffTimeout = 10 * time.Second
+
+// differential pairs the solved parts of a day with their reference solvers
+type differential struct {
+	day     int
+	maxSize int                                       // Largest size generated by default, the references of some days are slow
+	parts   map[int]func(input string) (int64, error) // Reference solver of each part
+	shrink  func(input string) []string               // Smaller inputs to try after a disagreement
+}
+
+var differentials = []differential{
+	{day: 2, maxSize: 20, parts: map[int]func(string) (int64, error){1: refDay2Part1, 2: refDay2Part2}, shrink: shrinkLines},
+	{day: 6, maxSize: 12, parts: map[int]func(string) (int64, error){1: refDay6Part1, 2: refDay6Part2}, shrink: shrinkGrid},
+	{day: 9, maxSize: 20, parts: map[int]func(string) (int64, error){2: refDay9Part2}, shrink: shrinkDiskMap},
+	{day: 11, maxSize: 4, parts: map[int]func(string) (int64, error){1: refDay11Part1, 2: refDay11Part2}, shrink: shrinkFields},
+	{day: 12, maxSize: 12, parts: map[int]func(string) (int64, error){1: refDay12Part1}, shrink: shrinkGrid},
+	{day: 13, maxSize: 20, parts: map[int]func(string) (int64, error){1: refDay13Part1}, shrink: shrinkStanzas},
+	{day: 14, maxSize: 20, parts: map[int]func(string) (int64, error){1: refDay14Part1}, shrink: shrinkLines},
+}
+
+// TestDifferential runs the real solvers and the reference solvers on
+// generated inputs of growing size. The first disagreement of each part is
+// shrunk and reported with the smallest input that still shows it.
+//
+//	go test ./Day -run Differential -diff.inputs 200 -diff.size 30
+func TestDifferential(t *testing.T) {
+	densities := []float64{gen.DefaultDensity, 0, 0.3, 0.7}
+	for _, dt := range differentials {
+		t.Run(fmt.Sprintf("day%d", dt.day), func(t *testing.T) {
+			p, _ := registry.Lookup(2024, dt.day)
+			generator := p.New().(gen.Generator)
+			maxSize := cmp.Or(*diffSize, dt.maxSize)
+			for _, part := range slices.Sorted(maps.Keys(dt.parts)) {
+				check := func(input string) (string, bool) {
+					return diffCheck(t.Context(), p, part, dt.parts[part], input)
+				}
+			search:
+				for size := 1; size <= maxSize; size++ {
+					for seed := range uint64(*diffInputs) {
+						var b bytes.Buffer
+						opts := gen.Options{Size: size, Density: densities[seed%uint64(len(densities))]}
+						if err := gen.Write(generator, &b, seed, opts); err != nil {
+							continue
+						}
+						if _, disagree := check(b.String()); !disagree {
+							continue
+						}
+						input := diffShrink(b.String(), dt.shrink, check)
+						report, _ := check(input)
+						t.Errorf("part %d, size %d seed %d density %g: %s, on\n%s", part, size, seed, opts.Density, report, input)
+						break search
+					}
+				}
+			}
+		})
+	}
+}
+
+// diffCheck solves a part of an input with the real solver and the reference,
+// and describes their disagreement. Inputs the reference or the parser reject
+// never disagree, a panic of the parser or the solver always does.
+func diffCheck(ctx context.Context, p registry.Puzzle, part int, ref func(string) (int64, error), input string) (string, bool) {
+	want, err := ref(input)
+	if err != nil {
+		return "", false
+	}
+	solver := p.New()
+	accepted, err := diffParse(solver, input)
+	if err != nil {
+		return fmt.Sprintf("parser failed with %v, reference answered %d", err, want), true
+	}
+	if !accepted {
+		return "", false
+	}
+
+	type answer struct {
+		got int64
+		err error
+	}
+	done := make(chan answer, 1)
+	ctx, cancel := context.WithTimeout(ctx, diffTimeout)
+	defer cancel()
+	go func() {
+		// A panic is a disagreement like any other, to be shrunk and reported
+		defer func() {
+			if r := recover(); r != nil {
+				done <- answer{err: fmt.Errorf("panic: %v", r)}
+			}
+		}()
+		got, err := registry.Solve(ctx, solver, part)
+		done <- answer{got, err}
+	}()
+	select {
+	case a := <-done:
+		if a.err != nil {
+			return fmt.Sprintf("solver failed with %v, reference answered %d", a.err, want), true
+		}
+		if a.got != want {
+			return fmt.Sprintf("solver answered %d, reference %d", a.got, want), true
+		}
+		return "", false
+	case <-time.After(diffTimeout):
+		return fmt.Sprintf("solver still running after %v, reference answered %d", diffTimeout, want), true
+	}
+}
+
+// diffParse parses input and reports whether the parser accepted it, a panic
+// of the parser being returned as an error
+func diffParse(solver registry.Solver, input string) (accepted bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return solver.Parse(strings.NewReader(input)) == nil, nil
+}
+
+// diffShrink replaces input by the smaller inputs of shrink for as long as
+// one of them still disagrees
+func diffShrink(input string, shrink func(string) []string, check func(string) (string, bool)) string {
+	for shrunk := true; shrunk; {
+		shrunk = false
+		for _, smaller := range shrink(input) {
+			if _, disagree := check(smaller); disagree {
+				input, shrunk = smaller, true
+				break
+			}
+		}
+	}
+	return input
+}
+
+// splitLines returns the lines of an input without their line endings
+func splitLines(input string) []string {
+	return strings.Split(strings.TrimRight(input, "\n"), "\n")
+}
+
+// shrinkLines drops one line
+func shrinkLines(input string) []string {
+	lines := splitLines(input)
+	var smaller []string
+	for i := range lines {
+		if len(lines) > 1 {
+			smaller = append(smaller, strings.Join(slices.Delete(slices.Clone(lines), i, i+1), "\n")+"\n")
+		}
+	}
+	return smaller
+}
+
+// shrinkStanzas drops one group of lines between blank lines
+func shrinkStanzas(input string) []string {
+	stanzas := strings.Split(strings.TrimRight(input, "\n"), "\n\n")
+	var smaller []string
+	for i := range stanzas {
+		if len(stanzas) > 1 {
+			smaller = append(smaller, strings.Join(slices.Delete(slices.Clone(stanzas), i, i+1), "\n\n")+"\n")
+		}
+	}
+	return smaller
+}
+
+// shrinkFields drops one field of a single line input
+func shrinkFields(input string) []string {
+	fields := strings.Fields(input)
+	var smaller []string
+	for i := range fields {
+		if len(fields) > 1 {
+			smaller = append(smaller, strings.Join(slices.Delete(slices.Clone(fields), i, i+1), " ")+"\n")
+		}
+	}
+	return smaller
+}
+
+// shrinkDiskMap drops one file and the free space before it, or the last file
+func shrinkDiskMap(input string) []string {
+	digits := strings.TrimSpace(input)
+	var smaller []string
+	for i := 1; i+1 < len(digits); i += 2 {
+		smaller = append(smaller, digits[:i]+digits[i+2:]+"\n")
+	}
+	if len(digits) > 1 {
+		smaller = append(smaller, digits[:len(digits)-2]+"\n")
+	}
+	return smaller
+}
+
+// shrinkGrid drops one row or one column of a map
+func shrinkGrid(input string) []string {
+	rows := splitLines(input)
+	var smaller []string
+	for i := range rows {
+		if len(rows) > 1 {
+			smaller = append(smaller, strings.Join(slices.Delete(slices.Clone(rows), i, i+1), "\n")+"\n")
+		}
+	}
+	for c := range len(rows[0]) {
+		if len(rows[0]) == 1 {
+			break
+		}
+		var sb strings.Builder
+		for _, row := range rows {
+			sb.WriteString(row[:c] + row[c+1:] + "\n")
+		}
+		smaller = append(smaller, sb.String())
+	}
+	return smaller
+}
+
+// panicSolver panics in Part1, as a solver with an index out of range would
+type panicSolver struct{}
+
+func (panicSolver) Parse(r io.Reader) error                  { return nil }
+func (panicSolver) Part1(ctx context.Context) (int64, error) { panic("index out of range") }
+func (panicSolver) Part2(ctx context.Context) (int64, error) { return 0, nil }
+
+// TestDiffCheckPanic checks that a panicking solver is reported as a disagreement
+func TestDiffCheckPanic(t *testing.T) {
+	p := registry.Puzzle{New: func() registry.Solver { return panicSolver{} }}
+	ref := func(string) (int64, error) { return 1, nil }
+	report, disagree := diffCheck(t.Context(), p, 1, ref, "")
+	if !disagree || !strings.Contains(report, "index out of range") {
+		t.Errorf("diffCheck = %q, %v, want a disagreement reporting the panic", report, disagree)
+	}
+}
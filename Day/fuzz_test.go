@@ -0,0 +1,62 @@
+package Day
+
+import (
+	"adventcode2024/input"
+	"adventcode2024/lint"
+	"adventcode2024/registry"
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+)
+
+// fuzzParse seeds f with the examples of a day and checks that the
+// parser and the linter of the day never panic on any other input. Parse
+// either accepts the input or rejects it with a *input.ParseError, Lint
+// reports problems but never fails. The real inputs are left out, seeds of
+// that size slow the fuzzer to a few inputs a second.
+//
+//	go test ./Day -run '^$' -fuzz FuzzDay7 -fuzztime 30s
+func fuzzParse(f *testing.F, day int) {
+	for _, v := range testLoader.Available(day) {
+		if v.Kind != input.Example {
+			continue
+		}
+		data, err := os.ReadFile(testLoader.Path(day, v))
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+	p, ok := registry.Lookup(2024, day)
+	if !ok {
+		f.Fatalf("day %d is not registered", day)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var pe *input.ParseError
+		if err := p.New().Parse(bytes.NewReader(data)); err != nil && !errors.As(err, &pe) {
+			t.Errorf("Parse returned %T %v, want a *input.ParseError", err, err)
+		}
+		if linter, ok := p.New().(lint.Linter); ok {
+			if _, err := linter.Lint(bytes.NewReader(data)); err != nil {
+				t.Errorf("Lint failed with %v", err)
+			}
+		}
+	})
+}
+
+func FuzzDay1(f *testing.F)  { fuzzParse(f, 1) }
+func FuzzDay2(f *testing.F)  { fuzzParse(f, 2) }
+func FuzzDay3(f *testing.F)  { fuzzParse(f, 3) }
+func FuzzDay4(f *testing.F)  { fuzzParse(f, 4) }
+func FuzzDay5(f *testing.F)  { fuzzParse(f, 5) }
+func FuzzDay6(f *testing.F)  { fuzzParse(f, 6) }
+func FuzzDay7(f *testing.F)  { fuzzParse(f, 7) }
+func FuzzDay8(f *testing.F)  { fuzzParse(f, 8) }
+func FuzzDay9(f *testing.F)  { fuzzParse(f, 9) }
+func FuzzDay10(f *testing.F) { fuzzParse(f, 10) }
+func FuzzDay11(f *testing.F) { fuzzParse(f, 11) }
+func FuzzDay12(f *testing.F) { fuzzParse(f, 12) }
+func FuzzDay13(f *testing.F) { fuzzParse(f, 13) }
+func FuzzDay14(f *testing.F) { fuzzParse(f, 14) }
@@ -0,0 +1,107 @@
+package Day
+
+import (
+	"adventcode2024/input"
+	"adventcode2024/lint"
+	"adventcode2024/registry"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestLint checks that every problem of an input is reported, not only the first
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name  string
+		day   int
+		input string
+		want  []string // line:column:text of each problem
+	}{
+		{"day1", 1, "3   4\n4   x\n5\n-1   2\n", []string{`2:5:"x"`, `3:1:"5"`, `4:1:"-1"`}},
+		{"day1 empty", 1, "\n\n", []string{`1:0:""`}},
+		{"day2", 2, "7 6 4\n1 2 ? 4\n\n8 -1\n", []string{`2:5:"?"`, `3:1:""`, `4:3:"-1"`}},
+		{"day3 empty", 3, "", []string{`1:0:""`}},
+		{"day4", 4, "XMAS\nXM\nXMAZ\n", []string{`2:1:"XM"`, `3:4:"Z"`}},
+		{"day5", 5, "47|53\n47-61\n\n75,47,61\n75,47\n1,1,2\n", []string{`2:1:"47-61"`, `5:1:"75,47"`, `6:3:"1"`}},
+		{"day5 no updates", 5, "47|53\n", []string{`0:0:""`}},
+		{"day6", 6, "..#.\n.^x\n^...\n", []string{`2:1:".^x"`, `2:3:"x"`, `3:1:"^"`}},
+		{"day6 no guard", 6, "..#\n...\n", []string{`0:0:""`}},
+		{"day7", 7, "190: 10 19\n83 17 5\n3267: 81 x 27\n", []string{`2:1:"83 17 5"`, `3:10:"x"`}},
+		{"day8", 8, "..a\n.#.\n..\n", []string{`2:2:"#"`, `3:1:".."`}},
+		{"day9", 9, "2333x3\n45\n", []string{`1:5:"x"`, `2:1:"45"`}},
+		{"day10", 10, "0123\n12x4\n98\n", []string{`2:3:"x"`, `3:1:"98"`}},
+		{"day11", 11, "125 17a -3\n", []string{`1:5:"17a"`, `1:9:"-3"`}},
+		{"day12", 12, "AAB\nA1b\n", []string{`2:2:"1"`, `2:3:"b"`}},
+		{"day13", 13, "Button A: X+94, Y+34\nButton B: X+0, Y+67\nPrize: X=8400, Y=5400\n\n" +
+			"Button A: X+26, Y+66\nPrize: X=1, Y=2\n\nButton A: X+1, Y+1\n",
+			[]string{`2:13:"0"`, `6:1:""`, `9:1:""`}},
+		{"day14", 14, "p=0,4 v=3,-3\np=101,3 v=-1,200\np=1,2 v=x,4\np=1,2\n", []string{`2:3:"101"`, `3:9:"x"`, `4:6:""`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, ok := registry.Lookup(2024, tt.day)
+			if !ok {
+				t.Fatalf("day %d is not registered", tt.day)
+			}
+			problems, err := p.New().(lint.Linter).Lint(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			problems.Sort()
+			var got []string
+			for _, pe := range problems {
+				got = append(got, fmt.Sprintf("%d:%d:%q", pe.Line, pe.Column, pe.Text))
+			}
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("problems %v, want %v\n%v", got, tt.want, problems)
+			}
+		})
+	}
+}
+
+// TestLintExampleRoom checks that the robots of a Day 14 example are held to
+// the smaller room of the puzzle text
+func TestLintExampleRoom(t *testing.T) {
+	p, _ := registry.Lookup(2024, 14)
+	linter := p.New().(lint.Linter)
+	registry.SetExample(linter, true)
+	problems, err := linter.Lint(strings.NewReader("p=3,2 v=1,0\np=11,4 v=3,-3\np=0,7 v=1,1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	problems.Sort()
+	var got []string
+	for _, pe := range problems {
+		got = append(got, fmt.Sprintf("%d:%d:%q", pe.Line, pe.Column, pe.Text))
+	}
+	if want := []string{`2:3:"11"`, `3:5:"7"`}; strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("problems %v, want %v", got, want)
+	}
+}
+
+// TestLintInputs checks that every input on disk that the solvers accept lints clean
+func TestLintInputs(t *testing.T) {
+	for _, p := range registry.All() {
+		linter, ok := p.New().(lint.Linter)
+		if !ok {
+			t.Errorf("day %d has no lint rules", p.Day)
+			continue
+		}
+		for _, v := range testLoader.Available(p.Day) {
+			file, err := testLoader.Open(p.Day, v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			registry.SetExample(linter, v.Kind == input.Example)
+			problems, err := linter.Lint(file)
+			file.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, pe := range problems {
+				t.Errorf("day %d %s: %v", p.Day, v, pe)
+			}
+		}
+	}
+}
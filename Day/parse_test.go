@@ -0,0 +1,121 @@
+package Day
+
+import (
+	"adventcode2024/input"
+	"adventcode2024/registry"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// TestLongLines checks that inputs of a single line far longer than a
+// bufio.Scanner token are read in full
+func TestLongLines(t *testing.T) {
+	tests := []struct {
+		day   int
+		input string
+		part  int
+		want  int64
+	}{
+		{3, strings.Repeat("xmul(2,4)&", 100_000), 1, 800_000},
+		{11, strings.Repeat("0 ", 100_000), 1, 100_000 * 19778}, // 25 blinks turn a 0 into 19778 stones
+	}
+	for _, tt := range tests {
+		p, _ := registry.Lookup(2024, tt.day)
+		solver := p.New()
+		if err := solver.Parse(strings.NewReader(tt.input)); err != nil {
+			t.Fatalf("day %d: %v", tt.day, err)
+		}
+		got, err := registry.Solve(t.Context(), solver, tt.part)
+		if err != nil || got != tt.want {
+			t.Errorf("day %d part %d = %d, %v, want %d", tt.day, tt.part, got, err, tt.want)
+		}
+	}
+}
+
+// TestParseErrors checks that malformed inputs are rejected with the position of the problem
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		day    int
+		input  string
+		line   int
+		column int
+		text   string
+	}{
+		{"day1 letter", 1, "3   4\n4   x\n", 2, 5, "x"},
+		{"day1 one column", 1, "3   4\n4\n", 2, 1, "4"},
+		{"day2 level", 2, "7 6 4\n1 2 ? 4\n", 2, 5, "?"},
+		{"day4 ragged", 4, "XMAS\nXM\n", 2, 1, "XM"},
+		{"day5 rule", 5, "47|53\n47-61\n\n75,47\n", 2, 1, "47-61"},
+		{"day5 update", 5, "47|53\n\n75,4x\n", 3, 4, "4x"},
+		{"day6 character", 6, "..#\n.^x\n", 2, 3, "x"},
+		{"day6 two guards", 6, "^.^\n...\n", 1, 3, "^"},
+		{"day7 value", 7, "190: 10 19\n83: 17 five\n", 2, 8, "five"},
+		{"day7 no values", 7, "190: 10 19\n5: \n", 2, 1, "5: "},
+		{"day8 character", 8, "..a\n.#.\n", 2, 2, "#"},
+		{"day9 digit", 9, "2333x\n", 1, 5, "x"},
+		{"day10 elevation", 10, "0123\n12x4\n", 2, 3, "x"},
+		{"day11 stone", 11, "125 17a\n", 1, 5, "17a"},
+		{"day12 plant", 12, "AAB\nA1B\n", 2, 2, "1"},
+		{"day13 prize", 13, "Button A: X+94, Y+34\nButton B: X+22, Y+67\nPrize: X=8400, Y=5400\n\n" +
+			"Button A: X+26, Y+66\nButton B: X+67, Y+21\nPrize: X=12748, Z=12176\n", 7, 17, "Z=12176"},
+		{"day13 button not moving", 13, "Button A: X+0, Y+34\nButton B: X+22, Y+67\nPrize: X=8400, Y=5400\n", 1, 13, "0"},
+		{"day14 velocity", 14, "p=0,4 v=3,-3\np=6,3 v=-1,b\n", 2, 12, "b"},
+		{"day14 outside the room", 14, "p=0,4 v=3,-3\np=500,2 v=1,0\n", 2, 3, "500"},
+		{"day14 negative position", 14, "p=0,-4 v=3,-3\n", 1, 5, "-4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, ok := registry.Lookup(2024, tt.day)
+			if !ok {
+				t.Fatalf("day %d is not registered", tt.day)
+			}
+			err := p.New().Parse(strings.NewReader(tt.input))
+			var pe *input.ParseError
+			if !errors.As(err, &pe) {
+				t.Fatalf("Parse error = %v, want a *input.ParseError", err)
+			}
+			if pe.Line != tt.line || pe.Column != tt.column || pe.Text != tt.text {
+				t.Errorf("error at %d:%d %q, want %d:%d %q (%v)", pe.Line, pe.Column, pe.Text, tt.line, tt.column, tt.text, err)
+			}
+		})
+	}
+}
+
+// TestDay3Scan checks that the streaming matcher finds the same instructions as
+// the regular expression it replaces, with the input arriving one byte at a time
+func TestDay3Scan(t *testing.T) {
+	re := regexp.MustCompile(`do\(\)|don't\(\)|mul\((\d+),(\d+)\)`)
+	rng := rand.New(rand.NewPCG(3, 3))
+	const alphabet = "mul(),don't0123456789x\n"
+	for range 2000 {
+		var sb strings.Builder
+		for range rng.IntN(40) {
+			sb.WriteByte(alphabet[rng.IntN(len(alphabet))])
+		}
+		memory := sb.String()
+
+		var want []day3Instruction
+		for _, match := range re.FindAllStringSubmatch(strings.ReplaceAll(memory, "\n", ""), -1) {
+			op, _, _ := strings.Cut(match[0], "(")
+			num1, _ := strconv.ParseInt(match[1], 10, 64)
+			num2, _ := strconv.ParseInt(match[2], 10, 64)
+			want = append(want, day3Instruction{op: op, num1: num1, num2: num2})
+		}
+		got, err := day3Scan(slog.New(slog.DiscardHandler), iotest.OneByteReader(strings.NewReader(memory)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(got, want) {
+			t.Fatalf("day3Scan(%q) = %v, want %v", memory, got, want)
+		}
+	}
+}
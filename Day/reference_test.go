@@ -0,0 +1,363 @@
+package Day
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Reference solvers answer a part the slow and obvious way, straight from the
+// puzzle text, for TestDifferential to hold the real solvers against. They
+// parse the input themselves and return errInvalid for an input the puzzle
+// would never give, such as a lab the guard cannot leave.
+
+// errInvalid marks an input outside the puzzle's rules
+var errInvalid = errors.New("not a valid puzzle input")
+
+// refFields returns the integers of every line of input
+func refFields(input string) ([][]int, error) {
+	var rows [][]int
+	for _, line := range strings.Split(strings.TrimRight(input, "\n"), "\n") {
+		var row []int
+		for _, f := range strings.Fields(line) {
+			n, err := strconv.Atoi(f)
+			if err != nil {
+				return nil, err
+			}
+			row = append(row, n)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// refSafe reports whether the levels all rise or all fall by 1 to 3
+func refSafe(levels []int) bool {
+	rising, falling := true, true
+	for i := 1; i < len(levels); i++ {
+		step := levels[i] - levels[i-1]
+		rising = rising && step >= 1 && step <= 3
+		falling = falling && step >= -3 && step <= -1
+	}
+	return rising || falling
+}
+
+// refDay2Part1 counts the safe reports
+func refDay2Part1(input string) (int64, error) {
+	reports, err := refFields(input)
+	if err != nil {
+		return 0, err
+	}
+	var safe int64
+	for _, levels := range reports {
+		if refSafe(levels) {
+			safe++
+		}
+	}
+	return safe, nil
+}
+
+// refDay2Part2 counts the reports that are safe with any one level removed, or none
+func refDay2Part2(input string) (int64, error) {
+	reports, err := refFields(input)
+	if err != nil {
+		return 0, err
+	}
+	var safe int64
+	for _, levels := range reports {
+		ok := refSafe(levels)
+		for i := range levels {
+			ok = ok || refSafe(slices.Delete(slices.Clone(levels), i, i+1))
+		}
+		if ok {
+			safe++
+		}
+	}
+	return safe, nil
+}
+
+// refLab is a lab of day 6, its rows and the guard's start
+type refLab struct {
+	rows  []string
+	start [2]int
+}
+
+// refDay6Lab reads a lab
+func refDay6Lab(input string) refLab {
+	lab := refLab{rows: strings.Split(strings.TrimRight(input, "\n"), "\n")}
+	for r, row := range lab.rows {
+		if c := strings.IndexByte(row, '^'); c >= 0 {
+			lab.start = [2]int{r, c}
+		}
+	}
+	return lab
+}
+
+// walk returns the cells the guard steps on before leaving the lab, with an
+// extra obstacle at block, and false if the guard never leaves
+func (lab refLab) walk(block [2]int) (map[[2]int]bool, bool) {
+	steps := [][2]int{{-1, 0}, {0, 1}, {1, 0}, {0, -1}} // North, east, south, west
+	type state struct {
+		pos [2]int
+		dir int
+	}
+	seen := map[state]bool{}
+	cells := map[[2]int]bool{}
+	pos, dir := lab.start, 0
+	for !seen[state{pos, dir}] {
+		seen[state{pos, dir}] = true
+		cells[pos] = true
+		next := [2]int{pos[0] + steps[dir][0], pos[1] + steps[dir][1]}
+		if next[0] < 0 || next[0] >= len(lab.rows) || next[1] < 0 || next[1] >= len(lab.rows[0]) {
+			return cells, true
+		}
+		if lab.rows[next[0]][next[1]] == '#' || next == block {
+			dir = (dir + 1) % 4
+		} else {
+			pos = next
+		}
+	}
+	return cells, false
+}
+
+// refDay6Part1 counts the cells the guard steps on
+func refDay6Part1(input string) (int64, error) {
+	cells, leaves := refDay6Lab(input).walk([2]int{-1, -1})
+	if !leaves {
+		return 0, errInvalid
+	}
+	return int64(len(cells)), nil
+}
+
+// refDay6Part2 tries an obstacle on every free cell but the start and counts
+// those that keep the guard in the lab forever
+func refDay6Part2(input string) (int64, error) {
+	lab := refDay6Lab(input)
+	if _, leaves := lab.walk([2]int{-1, -1}); !leaves {
+		return 0, errInvalid
+	}
+	var loops int64
+	for r, row := range lab.rows {
+		for c := range row {
+			block := [2]int{r, c}
+			if row[c] != '.' {
+				continue
+			}
+			if _, leaves := lab.walk(block); !leaves {
+				loops++
+			}
+		}
+	}
+	return loops, nil
+}
+
+// refDay9Part2 lays out every block, then moves each file, highest ID first,
+// to the leftmost free span before it that fits the whole file
+func refDay9Part2(input string) (int64, error) {
+	var blocks []int // File ID of each block, -1 when free
+	for i, ch := range strings.TrimSpace(input) {
+		id := -1
+		if i%2 == 0 {
+			id = i / 2
+		}
+		for range ch - '0' {
+			blocks = append(blocks, id)
+		}
+	}
+	for id := slices.Max(blocks); id >= 0; id-- {
+		start := slices.Index(blocks, id)
+		if start < 0 {
+			continue
+		}
+		size := 0
+		for start+size < len(blocks) && blocks[start+size] == id {
+			size++
+		}
+		for to := 0; to+size <= start; to++ {
+			if slices.ContainsFunc(blocks[to:to+size], func(b int) bool { return b != -1 }) {
+				continue
+			}
+			for k := range size {
+				blocks[to+k], blocks[start+k] = id, -1
+			}
+			break
+		}
+	}
+	var checksum int64
+	for pos, id := range blocks {
+		if id >= 0 {
+			checksum += int64(pos * id)
+		}
+	}
+	return checksum, nil
+}
+
+// refBlink applies the rules to every stone once
+func refBlink(stones []int64) []int64 {
+	var next []int64
+	for _, stone := range stones {
+		digits := strconv.FormatInt(stone, 10)
+		switch {
+		case stone == 0:
+			next = append(next, 1)
+		case len(digits)%2 == 0:
+			left, _ := strconv.ParseInt(digits[:len(digits)/2], 10, 64)
+			right, _ := strconv.ParseInt(digits[len(digits)/2:], 10, 64)
+			next = append(next, left, right)
+		default:
+			next = append(next, stone*2024)
+		}
+	}
+	return next
+}
+
+// refDay11Stones reads the line of stones
+func refDay11Stones(input string) ([]int64, error) {
+	var stones []int64
+	for _, f := range strings.Fields(input) {
+		stone, err := strconv.ParseInt(f, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		stones = append(stones, stone)
+	}
+	return stones, nil
+}
+
+// refDay11Part1 blinks 25 times at the whole row of stones and counts them
+func refDay11Part1(input string) (int64, error) {
+	stones, err := refDay11Stones(input)
+	if err != nil {
+		return 0, err
+	}
+	for range 25 {
+		stones = refBlink(stones)
+	}
+	return int64(len(stones)), nil
+}
+
+// refDay11Part2 blinks 75 times, keeping how many stones carry each number
+// since the order of the stones never matters
+func refDay11Part2(input string) (int64, error) {
+	stones, err := refDay11Stones(input)
+	if err != nil {
+		return 0, err
+	}
+	counts := map[int64]int64{}
+	for _, stone := range stones {
+		counts[stone]++
+	}
+	for range 75 {
+		next := map[int64]int64{}
+		for stone, n := range counts {
+			for _, s := range refBlink([]int64{stone}) {
+				next[s] += n
+			}
+		}
+		counts = next
+	}
+	var total int64
+	for _, n := range counts {
+		total += n
+	}
+	return total, nil
+}
+
+// refDay12Part1 floods each region and adds up area times perimeter, the
+// perimeter counting every side of a plot that faces another plant or the edge
+func refDay12Part1(input string) (int64, error) {
+	rows := strings.Split(strings.TrimRight(input, "\n"), "\n")
+	plant := func(r, c int) byte {
+		if r < 0 || r >= len(rows) || c < 0 || c >= len(rows[r]) {
+			return 0
+		}
+		return rows[r][c]
+	}
+	seen := map[[2]int]bool{}
+	var price int64
+	for r, row := range rows {
+		for c := range row {
+			if seen[[2]int{r, c}] {
+				continue
+			}
+			area, perimeter := 0, 0
+			queue := [][2]int{{r, c}}
+			seen[[2]int{r, c}] = true
+			for len(queue) > 0 {
+				p := queue[0]
+				queue = queue[1:]
+				area++
+				for _, step := range [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+					n := [2]int{p[0] + step[0], p[1] + step[1]}
+					if plant(n[0], n[1]) != row[c] {
+						perimeter++
+					} else if !seen[n] {
+						seen[n] = true
+						queue = append(queue, n)
+					}
+				}
+			}
+			price += int64(area * perimeter)
+		}
+	}
+	return price, nil
+}
+
+// refDay13Part1 tries every number of presses up to 100 of each button and
+// adds the cheapest win of each machine, A costing 3 tokens and B 1
+func refDay13Part1(input string) (int64, error) {
+	var tokens int64
+	for _, machine := range strings.Split(strings.TrimSpace(input), "\n\n") {
+		var ax, ay, bx, by, px, py int
+		_, err := fmt.Sscanf(machine, "Button A: X+%d, Y+%d\nButton B: X+%d, Y+%d\nPrize: X=%d, Y=%d",
+			&ax, &ay, &bx, &by, &px, &py)
+		if err != nil {
+			return 0, err
+		}
+		cheapest := -1
+		for a := 0; a <= 100; a++ {
+			for b := 0; b <= 100; b++ {
+				if a*ax+b*bx == px && a*ay+b*by == py && (cheapest < 0 || 3*a+b < cheapest) {
+					cheapest = 3*a + b
+				}
+			}
+		}
+		if cheapest >= 0 {
+			tokens += int64(cheapest)
+		}
+	}
+	return tokens, nil
+}
+
+// refDay14Part1 moves every robot 100 seconds at once in the 101x103 room of
+// the real input and multiplies the robots of the four quadrants
+func refDay14Part1(input string) (int64, error) {
+	var robots [][4]int
+	const width, height = 101, 103
+	for _, line := range strings.Split(strings.TrimSpace(input), "\n") {
+		var r [4]int
+		if _, err := fmt.Sscanf(line, "p=%d,%d v=%d,%d", &r[0], &r[1], &r[2], &r[3]); err != nil {
+			return 0, err
+		}
+		robots = append(robots, r)
+	}
+	var quadrants [4]int64
+	for _, r := range robots {
+		x := ((r[0]+100*r[2])%width + width) % width
+		y := ((r[1]+100*r[3])%height + height) % height
+		if x == width/2 || y == height/2 {
+			continue
+		}
+		q := 0
+		if x > width/2 {
+			q++
+		}
+		if y > height/2 {
+			q += 2
+		}
+		quadrants[q]++
+	}
+	return quadrants[0] * quadrants[1] * quadrants[2] * quadrants[3], nil
+}
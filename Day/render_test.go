@@ -0,0 +1,42 @@
+package Day
+
+import (
+	"adventcode2024/input"
+	"adventcode2024/registry"
+	"adventcode2024/render"
+	"testing"
+)
+
+// TestDraw checks that the drawable days draw one square per cell of the example
+func TestDraw(t *testing.T) {
+	tests := []struct {
+		day        int
+		rows, cols int
+	}{
+		{day: 6, rows: 10, cols: 10},
+		{day: 8, rows: 12, cols: 12},
+		{day: 12, rows: 10, cols: 10},
+	}
+	const scale = 3
+	for _, tt := range tests {
+		p, _ := registry.Lookup(2024, tt.day)
+		solver := p.New()
+		file, err := testLoader.Open(tt.day, input.Variant{Kind: input.Example, Example: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = solver.Parse(file)
+		file.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		img, err := solver.(render.Drawable).Draw(t.Context(), scale)
+		if err != nil {
+			t.Fatalf("day %d: %v", tt.day, err)
+		}
+		if b := img.Bounds(); b.Dx() != tt.cols*scale || b.Dy() != tt.rows*scale {
+			t.Errorf("day %d drew %v, want %dx%d", tt.day, b, tt.cols*scale, tt.rows*scale)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package Day
+
+import (
+	"adventcode2024/input"
+	"adventcode2024/registry"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testLoader reads the examples from the repository inputs directory
+var testLoader = &input.Loader{Dir: filepath.Join("..", "inputs")}
+
+// goldenCases are the examples from the puzzle texts and the answers published with them
+var goldenCases = []struct {
+	day   int
+	input string        // Input variant, "test", "test2", ...
+	want  map[int]int64 // Published answer per part, parts left out are not checked
+}{
+	{1, "test", map[int]int64{1: 11, 2: 31}},
+	{2, "test", map[int]int64{1: 2, 2: 4}},
+	{3, "test", map[int]int64{1: 161, 2: 48}},
+	{4, "test", map[int]int64{1: 18, 2: 9}},
+	{5, "test", map[int]int64{1: 143, 2: 123}},
+	{6, "test", map[int]int64{1: 41, 2: 6}},
+	{7, "test", map[int]int64{1: 3749, 2: 11387}},
+	{8, "test", map[int]int64{2: 34}},
+	{9, "test", map[int]int64{2: 2858}},
+	{10, "test", map[int]int64{1: 36, 2: 81}},
+	{11, "test", map[int]int64{1: 55312}},
+	{12, "test", map[int]int64{1: 1930}},
+	{12, "test2", map[int]int64{1: 140}},
+	{12, "test3", map[int]int64{1: 772}},
+	{13, "test", map[int]int64{1: 480}},
+	{14, "test", map[int]int64{1: 12}},
+	// advent new adds the examples of a new day above this line
+}
+
+// TestGolden parses each example with the registered solver of its day and checks the answers
+//
+//	go test ./Day -run Golden/day12
+func TestGolden(t *testing.T) {
+	for _, tc := range goldenCases {
+		t.Run(fmt.Sprintf("day%d/%s", tc.day, tc.input), func(t *testing.T) {
+			p, ok := registry.Lookup(2024, tc.day)
+			if !ok {
+				t.Fatalf("day %d is not registered", tc.day)
+			}
+			variant, err := input.ParseVariant(tc.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			file, err := testLoader.Open(tc.day, variant)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer file.Close()
+
+			solver := p.New()
+			registry.SetExample(solver, variant.Kind == input.Example)
+			if err := solver.Parse(file); err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			for part := 1; part <= 2; part++ {
+				want, ok := tc.want[part]
+				if !ok {
+					continue
+				}
+				got, err := registry.Solve(t.Context(), solver, part)
+				if err != nil {
+					t.Errorf("part %d: %v", part, err)
+					continue
+				}
+				if got != want {
+					t.Errorf("part %d = %d, want %d", part, got, want)
+				}
+			}
+		})
+	}
+}
+
+// TestDay7SingleValue checks that an equation of a single value is solved when
+// the value is its target, with no operator to place
+func TestDay7SingleValue(t *testing.T) {
+	p, _ := registry.Lookup(2024, 7)
+	for part := 1; part <= 2; part++ {
+		solver := p.New()
+		if err := solver.Parse(strings.NewReader("190: 190\n3: 4\n")); err != nil {
+			t.Fatal(err)
+		}
+		if got, err := registry.Solve(t.Context(), solver, part); err != nil || got != 190 {
+			t.Errorf("part %d = %d, %v, want 190", part, got, err)
+		}
+	}
+}
+
+// TestDay14FastRobots checks that robots moving more than a room per second wrap
+// around it. Each robot moves a whole number of rooms per second and stays put.
+func TestDay14FastRobots(t *testing.T) {
+	p, _ := registry.Lookup(2024, 14)
+	solver := p.New()
+	registry.SetExample(solver, true)
+	robots := "p=1,1 v=55,-70\np=9,1 v=-110,7\np=1,5 v=22,14\np=9,5 v=-11,-700\np=3,2 v=50,0\n"
+	if err := solver.Parse(strings.NewReader(robots)); err != nil {
+		t.Fatal(err)
+	}
+	// The fifth robot ends on (3+5000)%11 = 9, 2, a second robot in the top right quadrant
+	if got, err := solver.Part1(t.Context()); err != nil || got != 2 {
+		t.Errorf("Part1 = %d, %v, want 2", got, err)
+	}
+}
@@ -0,0 +1,48 @@
+// Package anim plays the frames of a simulation in the terminal.
+// Solvers whose parts are simulations embed a Recorder and emit a Frame after
+// every step; the CLI hands them to a Player that redraws the terminal in place.
+package anim
+
+// Frame is one state of a simulation
+type Frame struct {
+	Text  string // Drawing of the state, one line per row
+	Label string // What happened in this step, shown under the drawing
+}
+
+// Animated is implemented by solvers that emit frames, usually by embedding Recorder
+type Animated interface {
+	SetFrameHook(hook func(Frame))
+	SetFrameBuilderHook(hook func(build func() Frame))
+}
+
+// Recorder is embedded in a solver to emit frames to the hook set by the CLI.
+// Without a hook nothing is drawn, so recording costs nothing in normal runs.
+type Recorder struct {
+	hook func(build func() Frame)
+}
+
+// SetFrameHook sets the function receiving every emitted frame
+func (r *Recorder) SetFrameHook(hook func(Frame)) {
+	r.hook = func(build func() Frame) { hook(build()) }
+}
+
+// SetFrameBuilderHook sets a function receiving the builder of every emitted
+// frame, for hooks that keep only some frames to build just those. A builder
+// may be called until the next Emit, and the last one after the part returned,
+// so solvers do not change what they draw after their last Emit.
+func (r *Recorder) SetFrameBuilderHook(hook func(build func() Frame)) {
+	r.hook = hook
+}
+
+// Recording reports whether frames are wanted.
+// Drawing a frame is expensive, check it before building one.
+func (r *Recorder) Recording() bool {
+	return r != nil && r.hook != nil
+}
+
+// Emit sends a frame to the hook, built only when recording and the hook keeps it
+func (r *Recorder) Emit(frame func() Frame) {
+	if r.Recording() {
+		r.hook(frame)
+	}
+}
@@ -0,0 +1,169 @@
+package anim
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ANSI sequences used to redraw the terminal in place
+const (
+	clearScreen = "\x1b[2J\x1b[H"
+	cursorHome  = "\x1b[H"
+	clearLine   = "\x1b[K" // Clears the rest of the line after shorter text
+	clearBelow  = "\x1b[J" // Clears what a taller previous frame left below
+)
+
+// Speed limits, FPS is halved or doubled by the - and + commands
+const (
+	MinFPS     = 0.25
+	MaxFPS     = 240
+	DefaultFPS = 10
+)
+
+// Player draws frames at a fixed rate, redrawing the terminal in place.
+// Commands are read one per line from Controls:
+//
+//	p or an empty line  pause or resume
+//	n                   show the next frame and pause
+//	+ / -               double or halve the speed
+//	q                   stop playing
+type Player struct {
+	Out      io.Writer
+	Controls io.Reader // Commands, nil for none
+	FPS      float64   // Frames per second, DefaultFPS when 0
+
+	StartPaused bool // Show the first frame, then wait for a command
+}
+
+// playback is the state of one Play call
+type playback struct {
+	*Player
+	fps    float64
+	paused bool
+	shown  int   // Frames drawn so far
+	last   Frame // Frame on screen, redrawn when the status changes
+}
+
+// Play draws every frame received from frames until it is closed, q is
+// entered or ctx is cancelled. It returns the number of frames drawn.
+func (p *Player) Play(ctx context.Context, frames <-chan Frame) (int, error) {
+	pb := &playback{Player: p, fps: p.FPS}
+	if pb.fps <= 0 {
+		pb.fps = DefaultFPS
+	}
+	pb.fps = min(max(pb.fps, MinFPS), MaxFPS)
+	commands := p.commands(ctx)
+
+	fmt.Fprint(p.Out, clearScreen)
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	if p.StartPaused {
+		pb.paused = true
+		if !pb.next(ctx, frames) {
+			return pb.shown, ctx.Err()
+		}
+	}
+
+	for {
+		var tick <-chan time.Time
+		if !pb.paused {
+			tick = timer.C
+		}
+
+		select {
+		case <-ctx.Done():
+			return pb.shown, ctx.Err()
+
+		case cmd, ok := <-commands:
+			if !ok {
+				commands = nil
+				continue
+			}
+			switch cmd {
+			case "q":
+				return pb.shown, nil
+			case "", "p":
+				pb.paused = !pb.paused
+				timer.Reset(0)
+				pb.draw()
+			case "n":
+				pb.paused = true
+				if !pb.next(ctx, frames) {
+					return pb.shown, ctx.Err()
+				}
+			case "+":
+				pb.fps = min(pb.fps*2, MaxFPS)
+				pb.draw()
+			case "-":
+				pb.fps = max(pb.fps/2, MinFPS)
+				pb.draw()
+			}
+
+		case <-tick:
+			if !pb.next(ctx, frames) {
+				return pb.shown, ctx.Err()
+			}
+			timer.Reset(time.Duration(float64(time.Second) / pb.fps))
+		}
+	}
+}
+
+// next draws the next frame, it reports false once frames is closed or ctx is done
+func (pb *playback) next(ctx context.Context, frames <-chan Frame) bool {
+	select {
+	case frame, ok := <-frames:
+		if !ok {
+			return false
+		}
+		pb.last = frame
+		pb.shown++
+		pb.draw()
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// draw redraws the frame on screen and the status line under it
+func (pb *playback) draw() {
+	var sb strings.Builder
+	sb.WriteString(cursorHome)
+	for _, line := range strings.Split(strings.TrimSuffix(pb.last.Text, "\n"), "\n") {
+		sb.WriteString(line + clearLine + "\n")
+	}
+
+	state := "playing"
+	if pb.paused {
+		state = "paused"
+	}
+	fmt.Fprintf(&sb, "\nframe %d  %g fps  %s  %s%s\n", pb.shown, pb.fps, state, pb.last.Label, clearLine)
+	if pb.Controls != nil {
+		sb.WriteString("p pause, n step, + faster, - slower, q quit (then Enter)" + clearLine + "\n")
+	}
+	sb.WriteString(clearBelow)
+	io.WriteString(pb.Out, sb.String())
+}
+
+// commands reads one command per line from Controls until it ends or ctx is done
+func (p *Player) commands(ctx context.Context) <-chan string {
+	if p.Controls == nil {
+		return nil
+	}
+	commands := make(chan string)
+	go func() {
+		defer close(commands)
+		scanner := bufio.NewScanner(p.Controls)
+		for scanner.Scan() {
+			select {
+			case commands <- strings.TrimSpace(scanner.Text()):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return commands
+}
@@ -0,0 +1,107 @@
+package anim
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// framesOf returns a closed channel holding n numbered frames
+func framesOf(n int) <-chan Frame {
+	frames := make(chan Frame, n)
+	for i := 1; i <= n; i++ {
+		frames <- Frame{Text: fmt.Sprintf("#%d\n..", i), Label: fmt.Sprintf("step %d", i)}
+	}
+	close(frames)
+	return frames
+}
+
+// TestPlayAll checks that every frame is drawn in place with its counter
+func TestPlayAll(t *testing.T) {
+	var out bytes.Buffer
+	p := &Player{Out: &out, FPS: MaxFPS}
+	shown, err := p.Play(t.Context(), framesOf(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if shown != 3 {
+		t.Errorf("shown = %d, want 3", shown)
+	}
+	got := out.String()
+	if !strings.HasPrefix(got, clearScreen) {
+		t.Errorf("output does not start by clearing the screen: %q", got[:min(len(got), 20)])
+	}
+	if n := strings.Count(got, cursorHome); n != 4 {
+		t.Errorf("cursor went home %d times, want once to clear and once per frame", n)
+	}
+	for i := 1; i <= 3; i++ {
+		if !strings.Contains(got, fmt.Sprintf("frame %d  240 fps  playing  step %d", i, i)) {
+			t.Errorf("status line of frame %d missing", i)
+		}
+	}
+}
+
+// TestPlayControls checks stepping, speed changes and quitting from the command stream
+func TestPlayControls(t *testing.T) {
+	var out bytes.Buffer
+	controls, commands := io.Pipe()
+	p := &Player{Out: &out, Controls: controls, FPS: 1, StartPaused: true}
+
+	done := make(chan int)
+	go func() {
+		shown, _ := p.Play(t.Context(), framesOf(10))
+		done <- shown
+	}()
+	for _, cmd := range []string{"n", "n", "+", "q"} {
+		fmt.Fprintln(commands, cmd)
+	}
+	shown := <-done
+	commands.Close()
+
+	if shown != 3 {
+		t.Errorf("shown = %d, want 3: the first frame and two steps", shown)
+	}
+	if !strings.Contains(out.String(), "frame 3  2 fps  paused  step 3") {
+		t.Errorf("status after + missing from output:\n%s", out.String())
+	}
+}
+
+// TestRecorder checks that frames are only built while a hook is set
+func TestRecorder(t *testing.T) {
+	var r Recorder
+	built := 0
+	frame := func() Frame {
+		built++
+		return Frame{Label: "x"}
+	}
+	r.Emit(frame)
+	if built != 0 || r.Recording() {
+		t.Fatalf("frame built %d times without a hook", built)
+	}
+
+	var got []Frame
+	r.SetFrameHook(func(f Frame) { got = append(got, f) })
+	r.Emit(frame)
+	if built != 1 || len(got) != 1 {
+		t.Errorf("built %d frames, hook got %d, want 1 and 1", built, len(got))
+	}
+
+	// A builder hook decides which frames are built
+	emitted := 0
+	r.SetFrameBuilderHook(func(build func() Frame) {
+		if emitted++; emitted%2 == 0 {
+			got = append(got, build())
+		}
+	})
+	for range 4 {
+		r.Emit(frame)
+	}
+	if built != 3 || len(got) != 3 {
+		t.Errorf("built %d frames, hook kept %d, want 3 and 3", built, len(got))
+	}
+
+	var nilRecorder *Recorder
+	nilRecorder.Emit(frame) // A structure with no recorder must not panic
+}
@@ -0,0 +1,108 @@
+// Package answers keeps the manifest of known-correct puzzle answers
+// and checks registered solvers against it.
+package answers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// FileName is the manifest file name inside the inputs directory
+const FileName = "answers.json"
+
+// Entry is the known answer of one part of a day for one input variant
+type Entry struct {
+	Year   int    `json:"year"`
+	Day    int    `json:"day"`
+	Part   int    `json:"part"`
+	Input  string `json:"input"` // Input variant, "input", "test", "test2", ...
+	Answer int64  `json:"answer"`
+}
+
+// key identifies an entry without its answer
+type key struct {
+	year, day, part int
+	input           string
+}
+
+// Manifest holds known answers keyed by year, day, part and input variant
+type Manifest struct {
+	entries map[key]int64
+}
+
+// NewManifest returns an empty manifest
+func NewManifest() *Manifest {
+	return &Manifest{entries: make(map[key]int64)}
+}
+
+// Path returns the manifest path inside an inputs directory
+func Path(inputsDir string) string {
+	return filepath.Join(inputsDir, FileName)
+}
+
+// Load reads a manifest file. A missing file is an empty manifest.
+func Load(path string) (*Manifest, error) {
+	m := NewManifest()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for _, e := range entries {
+		m.Set(e)
+	}
+	return m, nil
+}
+
+// Save writes the manifest sorted by year, day, input and part so diffs stay small
+func (m *Manifest) Save(path string) error {
+	data, err := json.MarshalIndent(m.Entries(), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Lookup returns the known answer for a part of a day and input variant
+func (m *Manifest) Lookup(year, day, part int, input string) (int64, bool) {
+	answer, ok := m.entries[key{year: year, day: day, part: part, input: input}]
+	return answer, ok
+}
+
+// Set records an answer, replacing any previous answer for the same part and input
+func (m *Manifest) Set(e Entry) {
+	m.entries[key{year: e.Year, day: e.Day, part: e.Part, input: e.Input}] = e.Answer
+}
+
+// Entries returns every entry sorted by year, day, input and part
+func (m *Manifest) Entries() []Entry {
+	entries := make([]Entry, 0, len(m.entries))
+	for k, answer := range m.entries {
+		entries = append(entries, Entry{Year: k.year, Day: k.day, Part: k.part, Input: k.input, Answer: answer})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Year != b.Year {
+			return a.Year < b.Year
+		}
+		if a.Day != b.Day {
+			return a.Day < b.Day
+		}
+		if a.Input != b.Input {
+			return a.Input < b.Input
+		}
+		return a.Part < b.Part
+	})
+	return entries
+}
@@ -0,0 +1,101 @@
+package answers
+
+import (
+	"adventcode2024/registry"
+	"adventcode2024/results"
+	"adventcode2024/runner"
+	"context"
+	"errors"
+)
+
+// Status is the outcome of checking one answer against the manifest
+type Status string
+
+const (
+	Pass    Status = "pass"    // The solver matches the known answer
+	Fail    Status = "fail"    // The solver disagrees with the known answer or failed
+	Missing Status = "missing" // No known answer is recorded
+)
+
+// Check is the result of verifying one part of a day on one input variant
+type Check struct {
+	Year   int
+	Day    int
+	Part   int
+	Input  string // Input variant, "input", "test", "test2", ...
+	Want   int64  // Known answer, only set when Status is not Missing
+	Got    int64  // Answer from the solver, only set when Err is nil
+	Err    error  // Error from parsing or solving
+	Status Status
+}
+
+// Verify solves every puzzle on every available input variant with r, each
+// input under the runner's timeout, and compares each part with the manifest.
+// Checks are returned in puzzle, input and part order.
+func Verify(ctx context.Context, m *Manifest, r *runner.Runner, puzzles []registry.Puzzle) []Check {
+	var jobs []runner.Job
+	for _, p := range puzzles {
+		for _, variant := range r.Loader.Available(p.Day) {
+			jobs = append(jobs, runner.Job{Puzzle: p, Parts: []int{1, 2}, Variant: variant})
+		}
+	}
+	return Compare(m, r.Run(ctx, jobs))
+}
+
+// Compare checks results that were already solved, by a runner for instance,
+// against the manifest. Checks are returned in the order of the results.
+func Compare(m *Manifest, res []results.Result) []Check {
+	checks := make([]Check, len(res))
+	for i, r := range res {
+		checks[i] = compare(m, Check{Year: r.Year, Day: r.Day, Part: r.Part, Input: r.Input, Got: r.Answer, Err: r.Err})
+	}
+	return checks
+}
+
+// compare sets the status of a check from the known answer, if any
+func compare(m *Manifest, check Check) Check {
+	if check.Err != nil {
+		check.Got = 0
+	}
+	want, known := m.Lookup(check.Year, check.Day, check.Part, check.Input)
+	switch {
+	case !known:
+		check.Status = Missing
+	case check.Err == nil && check.Got == want:
+		check.Want = want
+		check.Status = Pass
+	default:
+		check.Want = want
+		check.Status = Fail
+	}
+	return check
+}
+
+// Record stores the answer of every missing check that solved without error.
+// It returns the number of answers recorded.
+func Record(m *Manifest, checks []Check) int {
+	recorded := 0
+	for _, c := range checks {
+		if c.Status != Missing || c.Err != nil {
+			continue
+		}
+		m.Set(Entry{Year: c.Year, Day: c.Day, Part: c.Part, Input: c.Input, Answer: c.Got})
+		recorded++
+	}
+	return recorded
+}
+
+// Failed reports whether any check failed
+func Failed(checks []Check) bool {
+	for _, c := range checks {
+		if c.Status == Fail {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNotImplemented reports whether a check is for a part that has not been solved yet
+func (c Check) IsNotImplemented() bool {
+	return errors.Is(c.Err, registry.ErrNotImplemented)
+}
@@ -0,0 +1,171 @@
+// Package aoc talks to the Advent of Code website.
+// It downloads puzzle inputs into the inputs directory and submits answers.
+// The HTTP layer is a Doer so tests can point a Client at a stand-in server.
+package aoc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	EnvSession     = "AOC_SESSION"              // Session token, the value of the session cookie
+	EnvBaseURL     = "AOC_BASE_URL"             // Overrides DefaultBaseURL
+	DefaultBaseURL = "https://adventofcode.com" // Where puzzles are fetched from
+)
+
+// DefaultInterval is the minimum time between two requests of a Client
+const DefaultInterval = 3 * time.Second
+
+// ErrNoSession is returned when no session token is configured
+var ErrNoSession = errors.New("no session token, set " + EnvSession + " or write it to the session file")
+
+// Doer sends HTTP requests, *http.Client implements it
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// Client makes throttled, authenticated requests to the Advent of Code website
+type Client struct {
+	BaseURL   string
+	Session   string
+	HTTP      Doer
+	Interval  time.Duration // Minimum time between two requests
+	UserAgent string
+
+	mu   sync.Mutex
+	last time.Time // When the previous request was sent
+}
+
+// NewClient returns a client for baseURL, DefaultBaseURL when empty,
+// that sends requests with http.DefaultClient
+func NewClient(baseURL, session string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return &Client{
+		BaseURL:   strings.TrimSuffix(baseURL, "/"),
+		Session:   session,
+		HTTP:      http.DefaultClient,
+		Interval:  DefaultInterval,
+		UserAgent: "github.com/nujact/adventcode2024-go advent CLI",
+	}
+}
+
+// BaseURL returns flag when set, then $AOC_BASE_URL, then DefaultBaseURL
+func BaseURL(flag string) string {
+	if flag != "" {
+		return flag
+	}
+	if env := os.Getenv(EnvBaseURL); env != "" {
+		return env
+	}
+	return DefaultBaseURL
+}
+
+// SessionFile returns the default session file, advent/session in the user config directory
+func SessionFile() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "advent", "session")
+}
+
+// HistoryFile returns the default submission log, advent/submissions.jsonl in the
+// user cache directory, out of the checked-in inputs directory
+func HistoryFile() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "advent", "submissions.jsonl")
+}
+
+// LoadSession returns the session token from $AOC_SESSION, or else from file
+func LoadSession(file string) (string, error) {
+	if env := strings.TrimSpace(os.Getenv(EnvSession)); env != "" {
+		return env, nil
+	}
+	if file == "" {
+		return "", ErrNoSession
+	}
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", ErrNoSession
+	}
+	if err != nil {
+		return "", err
+	}
+	session := strings.TrimSpace(string(data))
+	if session == "" {
+		return "", ErrNoSession
+	}
+	return session, nil
+}
+
+// throttle waits until Interval has passed since the previous request
+func (c *Client) throttle(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if wait := time.Until(c.last.Add(c.Interval)); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	c.last = time.Now()
+	return nil
+}
+
+// do sends a request with the session cookie after throttling and returns the body of a 200 response
+func (c *Client) do(ctx context.Context, method, path string, body io.Reader, contentType string) ([]byte, error) {
+	if c.Session == "" {
+		return nil, ErrNoSession
+	}
+	if err := c.throttle(ctx); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.AddCookie(&http.Cookie{Name: "session", Value: c.Session})
+	req.Header.Set("User-Agent", c.UserAgent)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return data, nil
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("%s %s: not found, the puzzle may not be unlocked yet", method, path)
+	case http.StatusBadRequest, http.StatusUnauthorized, http.StatusInternalServerError:
+		return nil, fmt.Errorf("%s %s: %s, the session token may be invalid or expired", method, path, resp.Status)
+	}
+	return nil, fmt.Errorf("%s %s: %s", method, path, resp.Status)
+}
@@ -0,0 +1,50 @@
+package aoc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// Input downloads the puzzle input of a day
+func (c *Client) Input(ctx context.Context, year, day int) ([]byte, error) {
+	return c.do(ctx, http.MethodGet, fmt.Sprintf("/%d/day/%d/input", year, day), nil, "")
+}
+
+// FetchInput makes sure path holds the puzzle input of a day.
+// A non-empty file at path is a cached input and is never downloaded again,
+// an empty placeholder is replaced, so path must belong to that year and day alone.
+// It reports whether a download happened.
+func (c *Client) FetchInput(ctx context.Context, year, day int, path string) (bool, error) {
+	if info, err := os.Stat(path); err == nil && info.Size() > 0 {
+		return false, nil
+	}
+
+	data, err := c.Input(ctx, year, day)
+	if err != nil {
+		return false, err
+	}
+	if len(data) == 0 {
+		return false, fmt.Errorf("day %d input is empty", day)
+	}
+
+	// Write through a temporary file so an interrupted download leaves no partial input
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".fetch-*")
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return false, err
+	}
+	if err := tmp.Close(); err != nil {
+		return false, err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return false, err
+	}
+	return true, os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,106 @@
+package aoc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a client for a stand-in server with a short interval
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	c := NewClient(server.URL, "secret")
+	c.HTTP = server.Client()
+	c.Interval = 50 * time.Millisecond
+	return c
+}
+
+// TestFetchInput checks the request, the cache and the replacement of empty placeholders
+func TestFetchInput(t *testing.T) {
+	var requests atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if cookie, err := r.Cookie("session"); err != nil || cookie.Value != "secret" {
+			http.Error(w, "Please log in", http.StatusBadRequest)
+			return
+		}
+		if r.URL.Path != "/2024/day/9/input" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("2333133121414131402\n"))
+	})
+
+	path := filepath.Join(t.TempDir(), "input9.txt")
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, wantFetched := range []bool{true, false} {
+		fetched, err := c.FetchInput(t.Context(), 2024, 9, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fetched != wantFetched {
+			t.Errorf("call %d fetched = %v, want %v", i+1, fetched, wantFetched)
+		}
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("server got %d requests, want 1", n)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "2333133121414131402\n" {
+		t.Errorf("cached input = %q", data)
+	}
+
+	if _, err := c.FetchInput(t.Context(), 2024, 10, filepath.Join(t.TempDir(), "input10.txt")); err == nil {
+		t.Error("fetching a locked day succeeded")
+	}
+	c.Session = "expired"
+	if _, err := c.Input(t.Context(), 2024, 9); err == nil {
+		t.Error("fetching with a bad session succeeded")
+	}
+}
+
+// TestThrottle checks that requests are spaced by the interval
+func TestThrottle(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("1\n"))
+	})
+
+	start := time.Now()
+	for range 3 {
+		if _, err := c.Input(t.Context(), 2024, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 2*c.Interval {
+		t.Errorf("3 requests took %v, want at least %v", elapsed, 2*c.Interval)
+	}
+}
+
+// TestLoadSession checks that the environment wins over the session file
+func TestLoadSession(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "session")
+	if err := os.WriteFile(file, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(EnvSession, "")
+	if session, err := LoadSession(file); err != nil || session != "from-file" {
+		t.Errorf("LoadSession = %q, %v, want from-file", session, err)
+	}
+	t.Setenv(EnvSession, "from-env")
+	if session, err := LoadSession(file); err != nil || session != "from-env" {
+		t.Errorf("LoadSession = %q, %v, want from-env", session, err)
+	}
+	t.Setenv(EnvSession, "")
+	if _, err := LoadSession(filepath.Join(t.TempDir(), "missing")); err != ErrNoSession {
+		t.Errorf("LoadSession without a token: %v, want ErrNoSession", err)
+	}
+}
@@ -0,0 +1,118 @@
+package aoc
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Submission is one line of the submission log
+type Submission struct {
+	Time    time.Time     `json:"time"`
+	Year    int           `json:"year"`
+	Day     int           `json:"day"`
+	Part    int           `json:"part"`
+	Answer  int64         `json:"answer"`
+	Verdict Verdict       `json:"verdict"`
+	Wait    time.Duration `json:"wait_ns,omitempty"`
+	Message string        `json:"message,omitempty"`
+}
+
+// Log is the local record of every submitted answer, one JSON object per line.
+// It lets the CLI refuse answers the website already judged and honour wait periods
+// across runs.
+type Log struct {
+	Path        string
+	Submissions []Submission // Oldest first
+}
+
+// LoadLog reads the submission log at path, a missing file is an empty log
+func LoadLog(path string) (*Log, error) {
+	l := &Log{Path: path}
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var s Submission
+		if err := json.Unmarshal(scanner.Bytes(), &s); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		l.Submissions = append(l.Submissions, s)
+	}
+	return l, scanner.Err()
+}
+
+// Append adds a submission to the log and writes it to the end of the file
+func (l *Log) Append(s Submission) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(l.Path), 0o755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(l.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	l.Submissions = append(l.Submissions, s)
+	return nil
+}
+
+// WaitUntil returns when the website accepts the next submission.
+// The wait applies to the whole account, so every puzzle is considered.
+func (l *Log) WaitUntil() time.Time {
+	var until time.Time
+	for _, s := range l.Submissions {
+		if end := s.Time.Add(s.Wait); end.After(until) {
+			until = end
+		}
+	}
+	return until
+}
+
+// Judged returns a verdict for answer that follows from earlier submissions
+// to the same part, so it does not need to be submitted again.
+// It reports false when the answer has to be submitted to find out.
+func (l *Log) Judged(year, day, part int, answer int64) (Verdict, bool) {
+	for _, s := range l.Submissions {
+		if s.Year != year || s.Day != day || s.Part != part || !s.Verdict.Checked() {
+			continue
+		}
+		switch {
+		case s.Verdict == Correct:
+			if s.Answer == answer {
+				return Correct, true
+			}
+			return Wrong, true
+		case s.Answer == answer:
+			return s.Verdict, true
+		case s.Verdict == TooHigh && answer > s.Answer:
+			return TooHigh, true
+		case s.Verdict == TooLow && answer < s.Answer:
+			return TooLow, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,111 @@
+package aoc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Verdict is the website's judgement of a submitted answer
+type Verdict string
+
+const (
+	Correct       Verdict = "correct"        // The answer is right
+	TooHigh       Verdict = "too high"       // The answer is wrong and above the right one
+	TooLow        Verdict = "too low"        // The answer is wrong and below the right one
+	Wrong         Verdict = "wrong"          // The answer is wrong, no hint given
+	Wait          Verdict = "wait"           // The answer was not checked, submitted too soon after the previous one
+	AlreadySolved Verdict = "already solved" // The part was already solved, the answer was not checked
+	Unknown       Verdict = "unknown"        // The response was not understood
+)
+
+// Checked reports whether the website actually judged the answer
+func (v Verdict) Checked() bool {
+	switch v {
+	case Correct, TooHigh, TooLow, Wrong:
+		return true
+	}
+	return false
+}
+
+// Outcome is the parsed response to a submitted answer
+type Outcome struct {
+	Verdict Verdict
+	Wait    time.Duration // How long to wait before the next submission, 0 when none was asked
+	Message string        // Text of the response with the markup removed
+}
+
+// Submit posts the answer to one part of a day and parses the response
+func (c *Client) Submit(ctx context.Context, year, day, part int, answer int64) (Outcome, error) {
+	form := url.Values{
+		"level":  {strconv.Itoa(part)},
+		"answer": {strconv.FormatInt(answer, 10)},
+	}
+	body, err := c.do(ctx, http.MethodPost, fmt.Sprintf("/%d/day/%d/answer", year, day),
+		strings.NewReader(form.Encode()), "application/x-www-form-urlencoded")
+	if err != nil {
+		return Outcome{}, err
+	}
+	return ParseOutcome(body), nil
+}
+
+var (
+	articlePattern = regexp.MustCompile(`(?s)<article[^>]*>(.*?)</article>`)
+	tagPattern     = regexp.MustCompile(`<[^>]*>`)
+	spacePattern   = regexp.MustCompile(`\s+`)
+
+	// "You have 1m 5s left to wait" after submitting too soon
+	leftPattern = regexp.MustCompile(`You have (?:(\d+)m )?(\d+)s left to wait`)
+	// "please wait one minute before trying again" after a wrong answer
+	waitPattern = regexp.MustCompile(`wait (\w+) minutes? before trying again`)
+)
+
+// waitWords are the minute counts the website spells out
+var waitWords = map[string]int{"one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "ten": 10}
+
+// ParseOutcome reads the verdict and wait period from the HTML page returned for a submission
+func ParseOutcome(body []byte) Outcome {
+	text := string(body)
+	if m := articlePattern.FindStringSubmatch(text); m != nil {
+		text = m[1]
+	}
+	text = tagPattern.ReplaceAllString(text, " ")
+	text = strings.TrimSpace(spacePattern.ReplaceAllString(text, " "))
+	outcome := Outcome{Verdict: Unknown, Message: text}
+
+	switch {
+	case strings.Contains(text, "That's the right answer"):
+		outcome.Verdict = Correct
+	case strings.Contains(text, "You gave an answer too recently"):
+		outcome.Verdict = Wait
+	case strings.Contains(text, "You don't seem to be solving the right level"):
+		outcome.Verdict = AlreadySolved
+	case strings.Contains(text, "That's not the right answer"):
+		switch {
+		case strings.Contains(text, "your answer is too high"):
+			outcome.Verdict = TooHigh
+		case strings.Contains(text, "your answer is too low"):
+			outcome.Verdict = TooLow
+		default:
+			outcome.Verdict = Wrong
+		}
+	}
+
+	if m := leftPattern.FindStringSubmatch(text); m != nil {
+		minutes, _ := strconv.Atoi(m[1])
+		seconds, _ := strconv.Atoi(m[2])
+		outcome.Wait = time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second
+	} else if m := waitPattern.FindStringSubmatch(text); m != nil {
+		minutes, ok := waitWords[m[1]]
+		if !ok {
+			minutes, _ = strconv.Atoi(m[1])
+		}
+		outcome.Wait = time.Duration(minutes) * time.Minute
+	}
+	return outcome
+}
@@ -0,0 +1,120 @@
+package aoc
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// page wraps a response message the way the website does
+func page(message string) string {
+	return `<html><body><main><article><p>` + message + `</p></article></main></body></html>`
+}
+
+// TestParseOutcome checks every kind of response to a submission
+func TestParseOutcome(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		verdict Verdict
+		wait    time.Duration
+	}{
+		{"correct", page(`That's the right answer! You are <em>one gold star</em> closer.`), Correct, 0},
+		{"too high", page(`That's not the right answer; your answer is too high. Please wait one minute before trying again.`), TooHigh, time.Minute},
+		{"too low", page(`That's not the right answer; your answer is too low. Please wait 5 minutes before trying again.`), TooLow, 5 * time.Minute},
+		{"wrong", page(`That's not the right answer. Please wait one minute before trying again.`), Wrong, time.Minute},
+		{"wait", page(`You gave an answer too recently; you have to wait after submitting an answer before trying again. You have 1m 5s left to wait.`), Wait, 65 * time.Second},
+		{"wait seconds", page(`You gave an answer too recently. You have 34s left to wait.`), Wait, 34 * time.Second},
+		{"already solved", page(`You don't seem to be solving the right level. Did you already complete it?`), AlreadySolved, 0},
+		{"unknown", `<html>maintenance</html>`, Unknown, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseOutcome([]byte(tt.body))
+			if got.Verdict != tt.verdict || got.Wait != tt.wait {
+				t.Errorf("ParseOutcome = %s, wait %v, want %s, wait %v (message %q)", got.Verdict, got.Wait, tt.verdict, tt.wait, got.Message)
+			}
+		})
+	}
+}
+
+// TestSubmit checks the request sent to the stand-in server
+func TestSubmit(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/2024/day/9/answer" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.FormValue("level") != "2" || r.FormValue("answer") != "6250605700557" {
+			w.Write([]byte(page(`That's not the right answer.`)))
+			return
+		}
+		w.Write([]byte(page(`That's the right answer!`)))
+	})
+
+	outcome, err := c.Submit(t.Context(), 2024, 9, 2, 6250605700557)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if outcome.Verdict != Correct {
+		t.Errorf("verdict = %s, want %s (message %q)", outcome.Verdict, Correct, outcome.Message)
+	}
+}
+
+// TestLog checks that the log survives a reload, decides known answers and tracks the wait period
+func TestLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "advent", "submissions.jsonl") // Made by the first Append
+	start := time.Date(2024, 12, 9, 6, 0, 0, 0, time.UTC)
+
+	l, err := LoadLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []Submission{
+		{Time: start, Year: 2024, Day: 9, Part: 1, Answer: 100, Verdict: TooHigh, Wait: time.Minute},
+		{Time: start.Add(time.Minute), Year: 2024, Day: 9, Part: 1, Answer: 10, Verdict: TooLow, Wait: time.Minute},
+		{Time: start.Add(90 * time.Second), Year: 2024, Day: 9, Part: 1, Answer: 50, Verdict: Wait, Wait: 30 * time.Second},
+	} {
+		if err := l.Append(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	l, err = LoadLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(l.Submissions) != 3 {
+		t.Fatalf("reloaded %d submissions, want 3", len(l.Submissions))
+	}
+	if got, want := l.WaitUntil(), start.Add(2*time.Minute); !got.Equal(want) {
+		t.Errorf("WaitUntil = %v, want %v", got, want)
+	}
+
+	judged := []struct {
+		part    int
+		answer  int64
+		verdict Verdict
+		ok      bool
+	}{
+		{1, 100, TooHigh, true},
+		{1, 150, TooHigh, true},
+		{1, 5, TooLow, true},
+		{1, 50, "", false}, // Only a wait response, never checked
+		{2, 100, "", false},
+	}
+	for _, j := range judged {
+		verdict, ok := l.Judged(2024, 9, j.part, j.answer)
+		if verdict != j.verdict || ok != j.ok {
+			t.Errorf("Judged(part %d, %d) = %q, %v, want %q, %v", j.part, j.answer, verdict, ok, j.verdict, j.ok)
+		}
+	}
+
+	if err := l.Append(Submission{Time: start.Add(3 * time.Minute), Year: 2024, Day: 9, Part: 1, Answer: 42, Verdict: Correct}); err != nil {
+		t.Fatal(err)
+	}
+	if verdict, _ := l.Judged(2024, 9, 1, 43); verdict != Wrong {
+		t.Errorf("Judged after the right answer = %q, want %q", verdict, Wrong)
+	}
+}
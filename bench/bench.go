@@ -0,0 +1,194 @@
+// Package bench times the parse, part 1 and part 2 phases of registered solvers
+// and compares the results of two runs.
+package bench
+
+import (
+	"adventcode2024/input"
+	"adventcode2024/registry"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"time"
+)
+
+// Phases of a day in the order they are timed
+const (
+	PhaseParse = "parse"
+	PhasePart1 = "part1"
+	PhasePart2 = "part2"
+)
+
+// Measurement is the timing of one phase of one day
+type Measurement struct {
+	Year        int    `json:"year"`
+	Day         int    `json:"day"`
+	Input       string `json:"input"` // Input variant, "input", "test", ...
+	Phase       string `json:"phase"`
+	Runs        int    `json:"runs"` // Number of times the phase ran
+	NsPerOp     int64  `json:"ns_per_op"`
+	AllocsPerOp int64  `json:"allocs_per_op"`
+	BytesPerOp  int64  `json:"bytes_per_op"`
+}
+
+// Report is the result of one benchmark run, written as JSON
+type Report struct {
+	GoVersion    string        `json:"go_version"`
+	Time         time.Time     `json:"time"`
+	Measurements []Measurement `json:"measurements"`
+}
+
+// NewReport returns an empty report stamped with the Go version and current time
+func NewReport() *Report {
+	return &Report{GoVersion: runtime.Version(), Time: time.Now().UTC()}
+}
+
+// Load reads a report written by Save
+func Load(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var r Report
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &r, nil
+}
+
+// Save writes the report as indented JSON
+func (r *Report) Save(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Day times each phase of a puzzle on one input, the parts in order.
+// Each phase runs repeatedly until it, or its round with the parsing before
+// each run, has taken at least minTime. Every run of a part gets a freshly
+// parsed solver so nothing cached or changed by an earlier run is reused, and
+// the parsing is left out of its time. Parts that are not implemented are left out.
+// Cancelling ctx stops the parts at their next check of ctx.
+func Day(ctx context.Context, p registry.Puzzle, data []byte, variant input.Variant, minTime time.Duration) ([]Measurement, error) {
+	measurements := make([]Measurement, 0, 3)
+	add := func(phase string, m Measurement) {
+		m.Year, m.Day, m.Input, m.Phase = p.Year, p.Day, variant.String(), phase
+		measurements = append(measurements, m)
+	}
+	newSolver := func() registry.Solver {
+		solver := p.New()
+		registry.SetExample(solver, variant.Kind == input.Example)
+		return solver
+	}
+
+	parse, err := measure(minTime, func() (func() error, error) {
+		solver := newSolver()
+		return func() error { return solver.Parse(bytes.NewReader(data)) }, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("parse: %w", err)
+	}
+	add(PhaseParse, parse)
+
+	for i, phase := range []string{PhasePart1, PhasePart2} {
+		part := i + 1
+		m, err := measure(minTime, func() (func() error, error) {
+			solver := newSolver()
+			if err := solver.Parse(bytes.NewReader(data)); err != nil {
+				return nil, fmt.Errorf("parse: %w", err)
+			}
+			return func() error {
+				_, err := registry.Solve(ctx, solver, part)
+				return err
+			}, nil
+		})
+		if errors.Is(err, registry.ErrNotImplemented) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("part %d: %w", part, err)
+		}
+		add(phase, m)
+	}
+
+	sortMeasurements(measurements)
+	return measurements, nil
+}
+
+// measure runs an op with a growing number of runs until one round takes at
+// least minTime, in the same way as testing.B, and reports the cost of a single
+// run. setup returns the op of each run and is not timed, like the code between
+// StopTimer and StartTimer, but its time counts towards ending the round.
+func measure(minTime time.Duration, setup func() (func() error, error)) (Measurement, error) {
+	var m Measurement
+	for runs := 1; ; {
+		elapsed, wall, allocs, bytes, err := round(runs, setup)
+		if err != nil {
+			return Measurement{}, err
+		}
+		m = Measurement{
+			Runs:        runs,
+			NsPerOp:     elapsed.Nanoseconds() / int64(runs),
+			AllocsPerOp: int64(allocs) / int64(runs),
+			BytesPerOp:  int64(bytes) / int64(runs),
+		}
+		if wall >= minTime || runs >= 1e9 {
+			return m, nil
+		}
+
+		// Aim 20% past minTime, growing at most 100x and at least by one run
+		next := int64(runs) * 100
+		if perRun := wall.Nanoseconds() / int64(runs); perRun > 0 {
+			next = min(next, minTime.Nanoseconds()*6/5/perRun)
+		}
+		runs = int(max(next, int64(runs)+1))
+	}
+}
+
+// round runs the op of setup the given number of times and returns the time
+// the ops took, the time the whole round took and the memory the ops allocated
+func round(runs int, setup func() (func() error, error)) (elapsed, wall time.Duration, allocs, bytes uint64, err error) {
+	var before, after runtime.MemStats
+	runtime.GC()
+	roundStart := time.Now()
+	for range runs {
+		op, err := setup()
+		if err != nil {
+			return 0, 0, 0, 0, err
+		}
+		runtime.ReadMemStats(&before)
+		start := time.Now()
+		err = op()
+		elapsed += time.Since(start)
+		runtime.ReadMemStats(&after)
+		if err != nil {
+			return 0, 0, 0, 0, err
+		}
+		allocs += after.Mallocs - before.Mallocs
+		bytes += after.TotalAlloc - before.TotalAlloc
+	}
+	return elapsed, time.Since(roundStart), allocs, bytes, nil
+}
+
+// sortMeasurements orders measurements by year, day, input and phase
+func sortMeasurements(measurements []Measurement) {
+	sort.Slice(measurements, func(i, j int) bool {
+		a, b := measurements[i], measurements[j]
+		if a.Year != b.Year {
+			return a.Year < b.Year
+		}
+		if a.Day != b.Day {
+			return a.Day < b.Day
+		}
+		if a.Input != b.Input {
+			return a.Input < b.Input
+		}
+		return a.Phase < b.Phase
+	})
+}
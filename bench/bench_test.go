@@ -0,0 +1,81 @@
+package bench
+
+import (
+	"adventcode2024/input"
+	"adventcode2024/registry"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+// fakeSolver only solves part 1, and only once per parse like a solver
+// that sorts its input in place or caches its answer
+type fakeSolver struct {
+	parsed, solved int
+}
+
+func (f *fakeSolver) Parse(r io.Reader) error {
+	_, err := io.ReadAll(r)
+	f.parsed++
+	return err
+}
+
+func (f *fakeSolver) Part1(ctx context.Context) (int64, error) {
+	if f.solved++; f.solved > f.parsed {
+		return 0, errors.New("solved twice on one parse")
+	}
+	return int64(f.parsed), nil
+}
+
+func (f *fakeSolver) Part2(ctx context.Context) (int64, error) { return 0, registry.ErrNotImplemented }
+
+// TestDay checks that every implemented phase is measured at least once, each run on its own parse
+func TestDay(t *testing.T) {
+	p := registry.Puzzle{Year: 2024, Day: 99, New: func() registry.Solver { return &fakeSolver{} }}
+	measurements, err := Day(t.Context(), p, []byte("1 2 3"), input.Variant{Kind: input.Example, Example: 1}, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	phases := make([]string, 0, len(measurements))
+	for _, m := range measurements {
+		phases = append(phases, m.Phase)
+		if m.Runs < 1 {
+			t.Errorf("%s ran %d times", m.Phase, m.Runs)
+		}
+		if m.Day != 99 || m.Input != "test" {
+			t.Errorf("%s labelled day %d input %q", m.Phase, m.Day, m.Input)
+		}
+	}
+	if len(phases) != 2 || phases[0] != PhaseParse || phases[1] != PhasePart1 {
+		t.Errorf("phases = %v, want [%s %s]", phases, PhaseParse, PhasePart1)
+	}
+}
+
+// TestCompare checks that only slowdowns past the threshold are flagged
+func TestCompare(t *testing.T) {
+	old := &Report{Measurements: []Measurement{
+		{Day: 6, Phase: PhasePart1, NsPerOp: 100},
+		{Day: 6, Phase: PhasePart2, NsPerOp: 100},
+		{Day: 9, Phase: PhasePart2, NsPerOp: 100},
+	}}
+	current := &Report{Measurements: []Measurement{
+		{Day: 6, Phase: PhasePart1, NsPerOp: 110},
+		{Day: 6, Phase: PhasePart2, NsPerOp: 150},
+		{Day: 9, Phase: PhasePart2, NsPerOp: 50},
+		{Day: 10, Phase: PhasePart1, NsPerOp: 50},
+	}}
+
+	deltas := Compare(old, current, 0.2)
+	if len(deltas) != 3 {
+		t.Fatalf("got %d deltas, want 3", len(deltas))
+	}
+	for _, d := range deltas {
+		wantSlower := d.Day == 6 && d.Phase == PhasePart2
+		if d.Slower != wantSlower {
+			t.Errorf("day %d %s ratio %.2f: Slower = %v, want %v", d.Day, d.Phase, d.Ratio, d.Slower, wantSlower)
+		}
+	}
+}
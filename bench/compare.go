@@ -0,0 +1,48 @@
+package bench
+
+// Delta compares one phase of one day between two reports
+type Delta struct {
+	Year   int
+	Day    int
+	Input  string
+	Phase  string
+	OldNs  int64   // Previous ns/op
+	NewNs  int64   // Current ns/op
+	Ratio  float64 // NewNs / OldNs
+	Slower bool    // The phase slowed down by more than the threshold
+}
+
+// Compare matches the measurements of two reports and computes how each phase changed.
+// A phase is flagged Slower when it takes more than (1 + threshold) times as long as before,
+// so a threshold of 0.2 flags slowdowns of more than 20%.
+// Phases missing from either report are left out.
+func Compare(old, current *Report, threshold float64) []Delta {
+	type key struct {
+		year, day    int
+		input, phase string
+	}
+	previous := make(map[key]Measurement, len(old.Measurements))
+	for _, m := range old.Measurements {
+		previous[key{m.Year, m.Day, m.Input, m.Phase}] = m
+	}
+
+	var deltas []Delta
+	for _, m := range current.Measurements {
+		before, ok := previous[key{m.Year, m.Day, m.Input, m.Phase}]
+		if !ok || before.NsPerOp == 0 {
+			continue
+		}
+		ratio := float64(m.NsPerOp) / float64(before.NsPerOp)
+		deltas = append(deltas, Delta{
+			Year:   m.Year,
+			Day:    m.Day,
+			Input:  m.Input,
+			Phase:  m.Phase,
+			OldNs:  before.NsPerOp,
+			NewNs:  m.NsPerOp,
+			Ratio:  ratio,
+			Slower: ratio > 1+threshold,
+		})
+	}
+	return deltas
+}
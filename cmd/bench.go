@@ -0,0 +1,120 @@
+package main
+
+import (
+	"adventcode2024/bench"
+	"adventcode2024/input"
+	"adventcode2024/registry"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+// errSlower is returned by bench when a phase slowed down past the threshold
+var errSlower = errors.New("bench: slowdowns found")
+
+// benchCommand times the parse, part 1 and part 2 phases of each day
+//
+//	advent bench -out before.json
+//	advent bench -day 6 -out after.json -compare before.json
+func benchCommand(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("bench", flag.ContinueOnError)
+	year := flags.Int("year", 2024, "puzzle year")
+	day := flags.Int("day", 0, "only benchmark this day")
+	variantFlag := flags.String("input", "input", "input to read: input, test, testN or a file path")
+	inputsDir := flags.String("inputs", "", "inputs directory (default $"+input.EnvDir+" or <repo>/inputs)")
+	benchTime := flags.Duration("benchtime", time.Second, "minimum time to run each phase")
+	out := flags.String("out", "", "write the results as JSON to this file")
+	comparePath := flags.String("compare", "", "compare with the JSON results of a previous run")
+	threshold := flags.Float64("threshold", 0.2, "flag phases that slowed down by more than this fraction")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	variant, err := input.ParseVariant(*variantFlag)
+	if err != nil {
+		return err
+	}
+	if variant.Kind == input.Stdin {
+		return errors.New("bench cannot read standard input")
+	}
+	loader, err := input.NewLoader(*inputsDir)
+	if err != nil && variant.Kind != input.Path {
+		return err
+	}
+	if loader == nil {
+		loader = &input.Loader{}
+	}
+
+	report := bench.NewReport()
+	for _, p := range registry.All() {
+		if p.Year != *year || (*day != 0 && p.Day != *day) {
+			continue
+		}
+		data, err := os.ReadFile(loader.Path(p.Day, variant))
+		if err != nil || len(data) == 0 {
+			if *day != 0 {
+				return fmt.Errorf("day %d %s input: %w", p.Day, variant, err)
+			}
+			continue
+		}
+
+		measurements, err := bench.Day(ctx, p, data, variant, *benchTime)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%d day %d: %v\n", p.Year, p.Day, err)
+			continue
+		}
+		report.Measurements = append(report.Measurements, measurements...)
+	}
+	printMeasurements(report.Measurements)
+
+	if *out != "" {
+		if err := report.Save(*out); err != nil {
+			return err
+		}
+	}
+	if *comparePath == "" {
+		return nil
+	}
+
+	old, err := bench.Load(*comparePath)
+	if err != nil {
+		return err
+	}
+	deltas := bench.Compare(old, report, *threshold)
+	printDeltas(deltas)
+	for _, d := range deltas {
+		if d.Slower {
+			return errSlower
+		}
+	}
+	return nil
+}
+
+// printMeasurements prints one row per day and phase
+func printMeasurements(measurements []bench.Measurement) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "day\tinput\tphase\truns\tns/op\tallocs/op\tB/op\t")
+	for _, m := range measurements {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%d\t%d\t%d\t\n", m.Day, m.Input, m.Phase, m.Runs, m.NsPerOp, m.AllocsPerOp, m.BytesPerOp)
+	}
+	w.Flush()
+}
+
+// printDeltas prints how each phase changed since the previous run
+func printDeltas(deltas []bench.Delta) {
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "day\tinput\tphase\told ns/op\tnew ns/op\tdelta\t")
+	for _, d := range deltas {
+		flag := ""
+		if d.Slower {
+			flag = "SLOWER"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%d\t%+.1f%%\t%s\n", d.Day, d.Input, d.Phase, d.OldNs, d.NewNs, (d.Ratio-1)*100, flag)
+	}
+	w.Flush()
+}
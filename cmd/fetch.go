@@ -0,0 +1,72 @@
+package main
+
+import (
+	"adventcode2024/aoc"
+	"adventcode2024/input"
+	"adventcode2024/registry"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+)
+
+// fetchCommand downloads puzzle inputs into the inputs directory.
+// Inputs already on disk are never downloaded again.
+//
+//	advent fetch -day 9
+//	advent fetch -all
+func fetchCommand(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("fetch", flag.ContinueOnError)
+	day := flags.Int("day", 0, "day to fetch")
+	all := flags.Bool("all", false, "fetch the input of every registered day")
+	inputsDir := flags.String("inputs", "", "inputs directory (default $"+input.EnvDir+" or <repo>/inputs)")
+	baseURL := flags.String("base-url", "", "website to fetch from (default $"+aoc.EnvBaseURL+" or "+aoc.DefaultBaseURL+")")
+	sessionFile := flags.String("session-file", aoc.SessionFile(), "file holding the session token when $"+aoc.EnvSession+" is not set")
+	interval := flags.Duration("interval", aoc.DefaultInterval, "minimum time between two requests")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var days []int
+	switch {
+	case *all:
+		for _, p := range registry.All() {
+			// Input files carry no year, only the inputs of input.Year are fetched
+			if p.Year == input.Year {
+				days = append(days, p.Day)
+			}
+		}
+	case *day >= 1 && *day <= 25:
+		days = []int{*day}
+	case *day == 0:
+		return errors.New("fetch needs -day N or -all")
+	default:
+		return fmt.Errorf("invalid -day %d, want 1 to 25", *day)
+	}
+
+	loader, err := input.NewLoader(*inputsDir)
+	if err != nil {
+		return err
+	}
+	// Cached inputs need no session, a missing token only fails a download
+	session, err := aoc.LoadSession(*sessionFile)
+	if err != nil && !errors.Is(err, aoc.ErrNoSession) {
+		return err
+	}
+	client := aoc.NewClient(aoc.BaseURL(*baseURL), session)
+	client.Interval = *interval
+
+	for _, d := range days {
+		path := loader.Path(d, input.Variant{Kind: input.Real})
+		fetched, err := client.FetchInput(ctx, input.Year, d, path)
+		if err != nil {
+			return fmt.Errorf("day %d: %w", d, err)
+		}
+		if fetched {
+			fmt.Printf("day %d: fetched %s\n", d, path)
+		} else {
+			fmt.Printf("day %d: cached %s\n", d, path)
+		}
+	}
+	return nil
+}
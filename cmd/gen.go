@@ -0,0 +1,58 @@
+package main
+
+import (
+	"adventcode2024/gen"
+	"adventcode2024/registry"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// genCommand writes a random input for a day, the same one for the same flags
+//
+//	advent gen -day 6 -size 500 -density 0.05 -seed 7 > lab.txt
+//	advent gen -day 13 -size 10000 -out machines.txt
+func genCommand(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("gen", flag.ContinueOnError)
+	year := flags.Int("year", 2024, "puzzle year")
+	day := flags.Int("day", 0, "puzzle day to generate an input for")
+	size := flags.Int("size", 0, "scale of the input, its meaning depends on the day; 0 uses the size of the real input")
+	density := flags.Float64("density", gen.DefaultDensity, "how full the input is from 0 to 1, its meaning depends on the day; -1 uses the day's default")
+	seed := flags.Uint64("seed", 1, "seed of the random choices")
+	out := flags.String("out", "", "file to write, default stdout")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *day == 0 {
+		return errors.New("gen needs -day N")
+	}
+	p, ok := registry.Lookup(*year, *day)
+	if !ok {
+		return fmt.Errorf("no solver registered for %d day %d", *year, *day)
+	}
+	generator, ok := p.New().(gen.Generator)
+	if !ok {
+		return fmt.Errorf("day %d has no input generator", *day)
+	}
+	opts := gen.Options{Size: *size, Density: *density}
+
+	if *out == "" {
+		return gen.Write(generator, os.Stdout, *seed, opts)
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := gen.Write(generator, f, *seed, opts); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, "wrote", *out)
+	return nil
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"adventcode2024/input"
+	"adventcode2024/lint"
+	"adventcode2024/registry"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// errLint is returned by lint when an input has problems
+var errLint = errors.New("lint: problems found")
+
+// lintCommand checks the structure of inputs without solving them, reporting
+// every problem of each input
+//
+//	advent lint
+//	advent lint -day 9
+//	advent lint -day 6 -input my/lab.txt
+func lintCommand(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("lint", flag.ContinueOnError)
+	year := flags.Int("year", 2024, "puzzle year")
+	day := flags.Int("day", 0, "only check this day")
+	variantFlag := flags.String("input", "", "input to check: input, test, testN, a file path or - for stdin; default every input file of the day")
+	inputsDir := flags.String("inputs", "", "inputs directory (default $"+input.EnvDir+" or <repo>/inputs)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var variant *input.Variant
+	if *variantFlag != "" {
+		if *day == 0 {
+			return errors.New("-input needs -day N")
+		}
+		v, err := input.ParseVariant(*variantFlag)
+		if err != nil {
+			return err
+		}
+		variant = &v
+	}
+	loader, err := input.NewLoader(*inputsDir)
+	if err != nil && (variant == nil || variant.Kind != input.Path && variant.Kind != input.Stdin) {
+		return err
+	}
+	if loader == nil {
+		loader = &input.Loader{}
+	}
+
+	checked, found := 0, 0
+	for _, p := range registry.All() {
+		if p.Year != *year || (*day != 0 && p.Day != *day) {
+			continue
+		}
+		linter, ok := p.New().(lint.Linter)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "day %d has no lint rules\n", p.Day)
+			continue
+		}
+
+		variants := lintVariants(loader, p.Day)
+		if variant != nil {
+			variants = []input.Variant{*variant}
+		}
+		for _, v := range variants {
+			problems, err := lintInput(linter, loader, p.Day, v)
+			if err != nil {
+				return err
+			}
+			checked++
+			found += len(problems)
+			for _, pe := range problems {
+				fmt.Println(pe)
+			}
+		}
+	}
+
+	fmt.Printf("%d problems in %d inputs\n", found, checked)
+	if found > 0 {
+		return errLint
+	}
+	return nil
+}
+
+// lintVariants returns the inputs of a day that exist on disk, empty ones included
+func lintVariants(loader *input.Loader, day int) []input.Variant {
+	var variants []input.Variant
+	if _, err := os.Stat(loader.Path(day, input.Variant{Kind: input.Real})); err == nil {
+		variants = append(variants, input.Variant{Kind: input.Real})
+	}
+	for n := 1; ; n++ {
+		example := input.Variant{Kind: input.Example, Example: n}
+		if _, err := os.Stat(loader.Path(day, example)); err != nil {
+			return variants
+		}
+		variants = append(variants, example)
+	}
+}
+
+// lintInput opens one input of a day and checks it
+func lintInput(linter lint.Linter, loader *input.Loader, day int, variant input.Variant) (lint.Problems, error) {
+	file, err := loader.Open(day, variant)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	registry.SetExample(linter, variant.Kind == input.Example)
+	problems, err := linter.Lint(file)
+	if err != nil {
+		return nil, err
+	}
+	name := loader.Path(day, variant)
+	if variant.Kind == input.Stdin {
+		name = "stdin"
+	}
+	problems.WithFile(name)
+	problems.Sort()
+	return problems, nil
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"adventcode2024/registry"
+	"context"
+	"flag"
+	"fmt"
+)
+
+// listCommand prints every registered puzzle
+func listCommand(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("list", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	for _, p := range registry.All() {
+		fmt.Printf("%d day %d\n", p.Year, p.Day)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"adventcode2024/logging"
+	"flag"
+	"log/slog"
+	"os"
+)
+
+// logFlags are the -v and -log flags of the commands that run solvers
+type logFlags struct {
+	verbose *bool
+	levels  *string
+}
+
+// addLogFlags adds -v and -log to a command's flags
+func addLogFlags(flags *flag.FlagSet) *logFlags {
+	return &logFlags{
+		verbose: flags.Bool("v", false, "log solver progress at info level"),
+		levels:  flags.String("log", "", "log levels, a level for every day and dayN=level for one, e.g. info,day6=debug"),
+	}
+}
+
+// logger returns the logger for solvers selected by the flags.
+// Diagnostics go to stderr so stdout only carries answers.
+// Without flags only warnings and errors are logged.
+func (l *logFlags) logger() (*slog.Logger, error) {
+	base := slog.LevelWarn
+	if *l.verbose {
+		base = slog.LevelInfo
+	}
+	levels, err := logging.ParseLevels(*l.levels, base)
+	if err != nil {
+		return nil, err
+	}
+	return logging.New(os.Stderr, levels), nil
+}
+
+// args returns the flags to pass on to another advent process
+func (l *logFlags) args() []string {
+	var args []string
+	if *l.verbose {
+		args = append(args, "-v")
+	}
+	if *l.levels != "" {
+		args = append(args, "-log", *l.levels)
+	}
+	return args
+}
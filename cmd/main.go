@@ -1,8 +1,11 @@
 package main
 
 import (
-	"adventcode2024/Day"
+	_ "adventcode2024/Day" // registers every day
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
 )
 
 /*   Advent of Code 2024
@@ -24,11 +27,64 @@ d88P  Y88b               888               d88P  Y88b d88P  Y88b d88P  Y88b    d
 Y88b  d88P Y88..88P Y88b 888 Y8b.          888"       Y88b  d88P 888"             888
  "Y8888P"   "Y88P"   "Y88888  "Y8888       888888888   "Y8888P"  888888888        888    */
 
+// commands maps each subcommand name to its implementation
+// Every command runs under a context that is cancelled on interrupt.
+var commands = map[string]func(ctx context.Context, args []string) error{
+	"bench":  benchCommand,
+	"fetch":  fetchCommand,
+	"gen":    genCommand,
+	"run":    runCommand,
+	"lint":   lintCommand,
+	"list":   listCommand,
+	"new":    newCommand,
+	"play":   playCommand,
+	"render": renderCommand,
+	"serve":  serveCommand,
+	"submit": submitCommand,
+	"verify": verifyCommand,
+	"watch":  watchCommand,
+}
+
 func main() {
-	fmt.Println("Advent of Code MAIN START\n-------------------------")
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	command, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "advent: unknown command %q\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	err := command(ctx, os.Args[2:])
+	stop()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "advent:", err)
+		os.Exit(1)
+	}
+}
+
+// usage prints the list of subcommands to stderr
+func usage() {
+	fmt.Fprintln(os.Stderr, `usage: advent <command> [flags]
 
-	// Day.Day1()
-	Day.Day14()
+commands:
+  run     run one day, or every day with -all
+  list    list the registered puzzles
+  verify  check every day against the known answers
+  lint    check the structure of inputs and report every problem
+  bench   time the parse and part phases of each day
+  fetch   download puzzle inputs into the inputs directory
+  gen     generate a random input of any size for stress testing
+  submit  solve a part on the real input and submit the answer
+  new     generate the solver, test and input files of a new day
+  play    animate a simulation day in the terminal
+  render  draw a day as a PNG picture or an animated GIF
+  serve   serve a local dashboard of answers, timings and pictures
+  watch   rebuild and re-run a day whenever its source or inputs change
 
-	fmt.Println("\n-------------------------\nAdvent of Code MAIN   END")
+Run "advent <command> -h" for the flags of a command.`)
 }
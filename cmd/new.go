@@ -0,0 +1,78 @@
+package main
+
+import (
+	"adventcode2024/input"
+	"adventcode2024/registry"
+	"adventcode2024/scaffold"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// newCommand generates the solver and input files of a new day and adds its
+// example to the table of examples.
+// The solver registers itself, so the day runs as soon as it is generated.
+//
+//	advent new -day 15
+//	advent new -day 15 -templates my/templates
+func newCommand(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("new", flag.ContinueOnError)
+	year := flags.Int("year", 2024, "puzzle year")
+	day := flags.Int("day", 0, "day to generate")
+	inputsDir := flags.String("inputs", "", "inputs directory (default $"+input.EnvDir+" or <repo>/inputs)")
+	templatesDir := flags.String("templates", "", "directory with "+scaffold.SolverTemplate+" and "+scaffold.TestTemplate+" (default the built-in templates)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *day < 1 || *day > 25 {
+		return fmt.Errorf("invalid -day %d, want 1 to 25", *day)
+	}
+	if _, ok := registry.Lookup(*year, *day); ok {
+		return fmt.Errorf("%d day %d is already registered", *year, *day)
+	}
+	loader, err := input.NewLoader(*inputsDir)
+	if err != nil {
+		return err
+	}
+	root, err := moduleRoot()
+	if err != nil {
+		return err
+	}
+
+	templates := scaffold.Default()
+	if *templatesDir != "" {
+		templates = os.DirFS(*templatesDir)
+	}
+	written, err := scaffold.Generate(templates, scaffold.NewData(*year, *day), filepath.Join(root, "Day"), loader.Dir)
+	for _, path := range written {
+		if filepath.Base(path) == scaffold.GoldenFile {
+			fmt.Println("updated", path)
+		} else {
+			fmt.Println("created", path)
+		}
+	}
+	return err
+}
+
+// moduleRoot returns the closest directory above the working directory holding a go.mod
+func moduleRoot() (string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	for dir := wd; ; dir = filepath.Dir(dir) {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir, nil
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+		if filepath.Dir(dir) == dir {
+			return "", errors.New("not inside the repository, no go.mod found")
+		}
+	}
+}
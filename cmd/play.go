@@ -0,0 +1,143 @@
+package main
+
+import (
+	"adventcode2024/anim"
+	"adventcode2024/input"
+	"adventcode2024/registry"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// playCommand animates a simulation day in the terminal, frame by frame.
+// Commands are typed on stdin followed by Enter, see anim.Player.
+//
+//	advent play -day 6
+//	advent play -day 14 -fps 4
+func playCommand(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("play", flag.ContinueOnError)
+	year := flags.Int("year", 2024, "puzzle year")
+	day := flags.Int("day", 0, "puzzle day to animate")
+	part := flags.Int("part", 0, "part to animate, 1 or 2; 0 animates the first solved part")
+	variantFlag := flags.String("input", "test", "input to read: input, test, testN or a file path")
+	inputsDir := flags.String("inputs", "", "inputs directory (default $"+input.EnvDir+" or <repo>/inputs)")
+	fps := flags.Float64("fps", anim.DefaultFPS, "frames per second")
+	paused := flags.Bool("paused", false, "start paused, step with n")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *day == 0 {
+		return errors.New("play needs -day N")
+	}
+	parts, err := playParts(*part)
+	if err != nil {
+		return err
+	}
+	if *variantFlag == "-" {
+		return errors.New("play reads its commands from stdin, give the input as a file")
+	}
+	p, ok := registry.Lookup(*year, *day)
+	if !ok {
+		return fmt.Errorf("no solver registered for %d day %d", *year, *day)
+	}
+	solver := registry.NewSolver(p, nil)
+	animated, ok := solver.(anim.Animated)
+	if !ok {
+		return fmt.Errorf("day %d has no animation", *day)
+	}
+
+	if err := parseVariant(solver, *day, *variantFlag, *inputsDir); err != nil {
+		return err
+	}
+
+	// The solver blocks on each frame until the player takes it, so it runs at the player's pace.
+	// Once playing stops the hook drops frames and the solver finishes at full speed.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	frames := make(chan anim.Frame)
+	animated.SetFrameHook(func(f anim.Frame) {
+		select {
+		case frames <- f:
+		case <-ctx.Done():
+		}
+	})
+
+	type answer struct {
+		part  int
+		value int64
+		err   error
+	}
+	solved := make(chan answer, 1)
+	go func() {
+		defer close(frames)
+		var a answer
+		a.part, a.value, a.err = solveFirst(ctx, solver, parts)
+		solved <- a
+	}()
+
+	player := &anim.Player{Out: os.Stdout, Controls: os.Stdin, FPS: *fps, StartPaused: *paused}
+	shown, playErr := player.Play(ctx, frames)
+	cancel()
+	result := <-solved
+
+	fmt.Printf("\n%d frames\n", shown)
+	if playErr != nil && !errors.Is(playErr, context.Canceled) {
+		return playErr
+	}
+	if errors.Is(result.err, context.Canceled) {
+		// Stopped with q before the part finished
+		return nil
+	}
+	if result.err != nil {
+		return fmt.Errorf("part %d: %w", result.part, result.err)
+	}
+	fmt.Printf("day %d part %d: %d\n", *day, result.part, result.value)
+	return nil
+}
+
+// playParts returns the parts to try for a -part flag, 0 tries part 1 then part 2
+func playParts(part int) ([]int, error) {
+	switch part {
+	case 0:
+		return []int{1, 2}, nil
+	case 1, 2:
+		return []int{part}, nil
+	}
+	return nil, fmt.Errorf("invalid -part %d, want 1 or 2", part)
+}
+
+// solveFirst solves the first of parts that is implemented and returns which part it solved
+func solveFirst(ctx context.Context, solver registry.Solver, parts []int) (part int, answer int64, err error) {
+	for _, part = range parts {
+		answer, err = registry.Solve(ctx, solver, part)
+		if !errors.Is(err, registry.ErrNotImplemented) {
+			break
+		}
+	}
+	return part, answer, err
+}
+
+// parseVariant parses the input named by an -input flag with solver
+func parseVariant(solver registry.Solver, day int, variantFlag, inputsDir string) error {
+	variant, err := input.ParseVariant(variantFlag)
+	if err != nil {
+		return err
+	}
+	loader, err := input.NewLoader(inputsDir)
+	if err != nil && variant.Kind != input.Path && variant.Kind != input.Stdin {
+		return err
+	}
+	if loader == nil {
+		loader = &input.Loader{}
+	}
+	file, err := loader.Open(day, variant)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	registry.SetExample(solver, variant.Kind == input.Example)
+	return input.WithFile(solver.Parse(file), loader.Path(day, variant))
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"adventcode2024/input"
+	"adventcode2024/profile"
+	"adventcode2024/registry"
+	"adventcode2024/results"
+	"adventcode2024/runner"
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// profileFlags are the profiling flags of the run command
+type profileFlags struct {
+	cpu, mem, block, trace *bool
+	dir                    *string
+	top                    *int
+}
+
+// addProfileFlags adds -cpuprofile, -memprofile, -blockprofile, -trace, -profiledir and -top
+func addProfileFlags(flags *flag.FlagSet) *profileFlags {
+	return &profileFlags{
+		cpu:   flags.Bool("cpuprofile", false, "write a CPU profile of the part to dayN-partP.cpu.pprof"),
+		mem:   flags.Bool("memprofile", false, "write a memory profile of the part to dayN-partP.mem.pprof, against dayN-partP.mem.base.pprof"),
+		block: flags.Bool("blockprofile", false, "write a blocking profile of the part to dayN-partP.block.pprof"),
+		trace: flags.Bool("trace", false, "write an execution trace of the part to dayN-partP.trace"),
+		dir:   flags.String("profiledir", ".", "directory the profiles are written to"),
+		top:   flags.Int("top", 0, "print the N hottest functions of each profile, needs the go tool"),
+	}
+}
+
+// options returns the profiles selected by the flags
+func (p *profileFlags) options() profile.Options {
+	return profile.Options{CPU: *p.cpu, Mem: *p.mem, Block: *p.block, Trace: *p.trace, Dir: *p.dir}
+}
+
+// profileRun parses a day's input, then solves one part with the profiles
+// recording. Only the part is profiled, the memory profile counting what the
+// part allocated against the baseline taken after parsing. Profile files and
+// summaries go to stderr so stdout only carries the result.
+func (p *profileFlags) profileRun(ctx context.Context, puzzle registry.Puzzle, part int, loader *input.Loader, variant input.Variant, timeout time.Duration, log *slog.Logger, out results.Writer) error {
+	result := results.Result{Year: puzzle.Year, Day: puzzle.Day, Part: part, Input: variant.String()}
+	solver := registry.NewSolver(puzzle, log)
+	file, err := loader.Open(puzzle.Day, variant)
+	if err != nil {
+		return err
+	}
+	registry.SetExample(solver, variant.Kind == input.Example)
+	err = solver.Parse(file)
+	file.Close()
+	if err != nil {
+		return fmt.Errorf("parse: %w", input.WithFile(err, loader.Path(puzzle.Day, variant)))
+	}
+
+	// The summaries run after the part, under ctx rather than the part's timeout
+	solveCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		solveCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	session, err := profile.Start(p.options(), fmt.Sprintf("day%d-part%d", puzzle.Day, part))
+	if err != nil {
+		return err
+	}
+	files, err := profilePart(solveCtx, session, solver, &result)
+	if err != nil {
+		return err
+	}
+
+	if err := out.Write(result); err != nil {
+		return err
+	}
+	if err := out.Flush(); err != nil {
+		return err
+	}
+	for _, path := range files {
+		fmt.Fprintln(os.Stderr, "wrote", path)
+	}
+	if *p.top <= 0 {
+		return nil
+	}
+	for _, path := range files {
+		if !profile.IsPprof(path) || profile.IsBaseline(path) {
+			continue
+		}
+		summary, err := profile.Top(ctx, path, *p.top)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "\n%s\n%s", path, summary)
+	}
+	return nil
+}
+
+// profilePart solves the part of result while session records and stops the
+// session however the part ends, so a panicking part still writes its profiles
+func profilePart(ctx context.Context, session *profile.Session, solver registry.Solver, result *results.Result) (files []string, err error) {
+	defer func() { files, err = session.Stop() }()
+	start := time.Now()
+	result.Answer, result.Err = runner.SolveSafely(ctx, solver, result.Part)
+	result.Duration = time.Since(start)
+	return nil, nil
+}
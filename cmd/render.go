@@ -0,0 +1,157 @@
+package main
+
+import (
+	"adventcode2024/anim"
+	"adventcode2024/input"
+	"adventcode2024/registry"
+	"adventcode2024/render"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// renderCommand draws a day as a PNG picture or an animated GIF of its simulation
+//
+//	advent render -day 12 -out garden.png
+//	advent render -day 6 -input test -out walk.gif
+func renderCommand(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("render", flag.ContinueOnError)
+	year := flags.Int("year", 2024, "puzzle year")
+	day := flags.Int("day", 0, "puzzle day to draw")
+	part := flags.Int("part", 0, "part whose frames make a GIF, 1 or 2; 0 uses the first solved part")
+	variantFlag := flags.String("input", "input", "input to read: input, test, testN or a file path")
+	inputsDir := flags.String("inputs", "", "inputs directory (default $"+input.EnvDir+" or <repo>/inputs)")
+	out := flags.String("out", "", "file to write, .png for a picture or .gif for an animation")
+	scale := flags.Int("scale", render.DefaultScale, "side of a cell in pixels")
+	delay := flags.Int("delay", 5, "time each GIF frame is shown, in hundredths of a second")
+	maxFrames := flags.Int("max-frames", 500, "most frames in a GIF, longer runs keep every n-th frame")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *day == 0 {
+		return errors.New("render needs -day N")
+	}
+	format := strings.ToLower(filepath.Ext(*out))
+	if format != ".png" && format != ".gif" {
+		return fmt.Errorf("render needs -out ending in .png or .gif, got %q", *out)
+	}
+	parts, err := playParts(*part)
+	if err != nil {
+		return err
+	}
+	if *scale < 1 || *maxFrames < 2 {
+		return errors.New("-scale must be at least 1 and -max-frames at least 2")
+	}
+
+	p, ok := registry.Lookup(*year, *day)
+	if !ok {
+		return fmt.Errorf("no solver registered for %d day %d", *year, *day)
+	}
+	solver := registry.NewSolver(p, nil)
+	if err := parseVariant(solver, *day, *variantFlag, *inputsDir); err != nil {
+		return err
+	}
+
+	drawable, isDrawable := solver.(render.Drawable)
+	animated, isAnimated := solver.(anim.Animated)
+
+	var frames []anim.Frame
+	switch {
+	case format == ".png" && isDrawable:
+		img, err := drawable.Draw(ctx, *scale)
+		if err != nil {
+			return err
+		}
+		return writeImage(*out, func(f *os.File) error { return render.WritePNG(f, img) })
+	case !isAnimated && isDrawable:
+		return fmt.Errorf("day %d has no animation, write a .png instead", *day)
+	case !isAnimated:
+		return fmt.Errorf("day %d cannot be drawn", *day)
+	}
+
+	// Keep at most maxFrames frames, halving the frame rate each time the limit is reached
+	sample := &frameSample{max: *maxFrames, stride: 1}
+	animated.SetFrameBuilderHook(sample.add)
+	if _, _, err := solveFirst(ctx, solver, parts); err != nil {
+		return err
+	}
+	frames = sample.frames()
+	if len(frames) == 0 {
+		return fmt.Errorf("day %d emitted no frames", *day)
+	}
+
+	if format == ".png" {
+		img := render.Image(render.Text(frames[len(frames)-1].Text), render.TextColor, *scale)
+		return writeImage(*out, func(f *os.File) error { return render.WritePNG(f, img) })
+	}
+	images := make([]image.Image, len(frames))
+	for i, frame := range frames {
+		images[i] = render.Image(render.Text(frame.Text), render.TextColor, *scale)
+	}
+	if err := writeImage(*out, func(f *os.File) error { return render.WriteGIF(f, images, *delay) }); err != nil {
+		return err
+	}
+	fmt.Printf("%d frames, one every %d steps\n", len(frames), sample.stride)
+	return nil
+}
+
+// frameSample keeps an evenly spaced sample of at most max frames, and always
+// the last one. Only the frames it keeps are built.
+type frameSample struct {
+	max    int
+	stride int // Only every stride-th frame is kept
+	seen   int
+	kept   []anim.Frame
+	last   func() anim.Frame // Builder of the last frame when it was not kept, nil when it was
+}
+
+// add is the frame builder hook of the solver
+func (s *frameSample) add(build func() anim.Frame) {
+	s.last = build
+	if s.seen%s.stride == 0 {
+		s.kept = append(s.kept, build())
+		s.last = nil
+		if len(s.kept) == s.max {
+			// Keep every other frame, the last kept one included when max is odd,
+			// and half as many from now on
+			n := (len(s.kept) + 1) / 2
+			for i := range n {
+				s.kept[i] = s.kept[2*i]
+			}
+			s.kept = s.kept[:n]
+			s.stride *= 2
+		}
+	}
+	s.seen++
+}
+
+// frames returns the sampled frames ending with the last frame emitted
+func (s *frameSample) frames() []anim.Frame {
+	if s.last != nil {
+		return append(s.kept, s.last())
+	}
+	return s.kept
+}
+
+// writeImage creates path and writes an image into it with encode
+func writeImage(path string, encode func(f *os.File) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := encode(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Println("wrote", path)
+	return nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"adventcode2024/input"
+	"adventcode2024/registry"
+	"adventcode2024/results"
+	"adventcode2024/runner"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// runCommand runs a single registered day, or every day with -all
+//
+//	advent run -day 7 -part 2 -input test
+//	advent run -day 7 -input my/input.txt
+//	advent run -all -j 4 -timeout 30s -format json
+//	advent run -day 6 -part 2 -cpuprofile -top 10
+func runCommand(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("run", flag.ContinueOnError)
+	year := flags.Int("year", 2024, "puzzle year")
+	day := flags.Int("day", 0, "puzzle day to run")
+	part := flags.Int("part", 0, "part to run, 1 or 2; 0 runs both")
+	variantFlag := flags.String("input", "input", "input to read: input, test, testN, a file path or - for stdin")
+	inputsDir := flags.String("inputs", "", "inputs directory (default $"+input.EnvDir+" or <repo>/inputs)")
+	all := flags.Bool("all", false, "run every registered day of the year")
+	format := flags.String("format", "table", "output format: table, json (one object per line) or csv")
+	workers := flags.Int("j", 1, "number of days to run at the same time")
+	timeout := flags.Duration("timeout", 0, "time limit for each day, 0 for none")
+	logs := addLogFlags(flags)
+	profiles := addProfileFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	out, err := results.NewWriter(os.Stdout, *format)
+	if err != nil {
+		return err
+	}
+	log, err := logs.logger()
+	if err != nil {
+		return err
+	}
+	variant, err := input.ParseVariant(*variantFlag)
+	if err != nil {
+		return err
+	}
+	loader, err := input.NewLoader(*inputsDir)
+	if err != nil && variant.Kind != input.Path && variant.Kind != input.Stdin {
+		return err
+	}
+	if loader == nil {
+		loader = &input.Loader{}
+	}
+
+	if *part < 0 || *part > 2 {
+		return fmt.Errorf("invalid -part %d, want 1 or 2", *part)
+	}
+	parts := []int{1, 2}
+	if *part != 0 {
+		parts = []int{*part}
+	}
+	if *workers < 1 {
+		return fmt.Errorf("invalid -j %d, want at least 1", *workers)
+	}
+
+	if profiles.options().Enabled() {
+		if *all || *day == 0 || *part == 0 {
+			return errors.New("profiling needs a single -day and -part")
+		}
+		p, ok := registry.Lookup(*year, *day)
+		if !ok {
+			return fmt.Errorf("no solver registered for %d day %d", *year, *day)
+		}
+		return profiles.profileRun(ctx, p, *part, loader, variant, *timeout, log, out)
+	}
+
+	var jobs []runner.Job
+	if *all {
+		for _, p := range registry.All() {
+			if p.Year == *year {
+				jobs = append(jobs, runner.Job{Puzzle: p, Parts: parts, Variant: variant})
+			}
+		}
+	} else {
+		if *day == 0 {
+			return errors.New("run needs -day N or -all")
+		}
+		p, ok := registry.Lookup(*year, *day)
+		if !ok {
+			return fmt.Errorf("no solver registered for %d day %d", *year, *day)
+		}
+		jobs = append(jobs, runner.Job{Puzzle: p, Parts: parts, Variant: variant})
+	}
+
+	r := &runner.Runner{Loader: loader, Workers: *workers, Timeout: *timeout, Log: log}
+	res := r.Run(ctx, jobs)
+
+	for _, result := range res {
+		if err := out.Write(result); err != nil {
+			return err
+		}
+	}
+	return out.Flush()
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"adventcode2024/answers"
+	"adventcode2024/bench"
+	"adventcode2024/dashboard"
+	"adventcode2024/input"
+	"adventcode2024/registry"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// serveCommand runs every day once and serves a dashboard of the answers on a local address
+//
+//	advent serve
+//	advent serve -addr localhost:9000 -bench before.json
+func serveCommand(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	year := flags.Int("year", 2024, "puzzle year")
+	addr := flags.String("addr", "localhost:8024", "address to listen on")
+	inputsDir := flags.String("inputs", "", "inputs directory (default $"+input.EnvDir+" or <repo>/inputs)")
+	manifestPath := flags.String("answers", "", "answers manifest (default <inputs>/"+answers.FileName+")")
+	benchPath := flags.String("bench", "", "JSON results of advent bench to show as timings")
+	workers := flags.Int("j", 1, "number of days to run at the same time")
+	timeout := flags.Duration("timeout", 0, "time limit for each day, 0 for none")
+	logs := addLogFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	log, err := logs.logger()
+	if err != nil {
+		return err
+	}
+	loader, err := input.NewLoader(*inputsDir)
+	if err != nil {
+		return err
+	}
+	if *manifestPath == "" {
+		*manifestPath = answers.Path(loader.Dir)
+	}
+	manifest, err := answers.Load(*manifestPath)
+	if err != nil {
+		return err
+	}
+	var report *bench.Report
+	if *benchPath != "" {
+		if report, err = bench.Load(*benchPath); err != nil {
+			return err
+		}
+	}
+	if *workers < 1 {
+		return fmt.Errorf("invalid -j %d, want at least 1", *workers)
+	}
+
+	server := &dashboard.Server{Loader: loader, Manifest: manifest, Bench: report, Workers: *workers, Timeout: *timeout, Log: log}
+	for _, p := range registry.All() {
+		if p.Year == *year {
+			server.Puzzles = append(server.Puzzles, p)
+		}
+	}
+	if len(server.Puzzles) == 0 {
+		return fmt.Errorf("no solvers registered for %d", *year)
+	}
+
+	// Listen first so a busy address fails before every day is solved
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("solving %d days\n", len(server.Puzzles))
+	if err := server.Refresh(ctx); err != nil {
+		listener.Close()
+		return err
+	}
+
+	srv := &http.Server{Handler: server.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	done := make(chan error, 1)
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		done <- srv.Shutdown(shutdown)
+	}()
+
+	fmt.Printf("serving http://%s\n", listener.Addr())
+	if err := srv.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return <-done
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"adventcode2024/aoc"
+	"adventcode2024/input"
+	"adventcode2024/registry"
+	"adventcode2024/runner"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+)
+
+// submitCommand solves one part on the real input and submits the answer.
+// Every submission is logged, answers the log already decides are not sent again.
+//
+//	advent submit -day 9 -part 2
+//	advent submit -day 9 -part 2 -wait
+func submitCommand(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("submit", flag.ContinueOnError)
+	year := flags.Int("year", 2024, "puzzle year")
+	day := flags.Int("day", 0, "puzzle day to submit")
+	part := flags.Int("part", 0, "part to submit, 1 or 2")
+	inputsDir := flags.String("inputs", "", "inputs directory (default $"+input.EnvDir+" or <repo>/inputs)")
+	baseURL := flags.String("base-url", "", "website to submit to (default $"+aoc.EnvBaseURL+" or "+aoc.DefaultBaseURL+")")
+	sessionFile := flags.String("session-file", aoc.SessionFile(), "file holding the session token when $"+aoc.EnvSession+" is not set")
+	history := flags.String("history", aoc.HistoryFile(), "submission log")
+	wait := flags.Bool("wait", false, "sleep through a pending wait period instead of failing")
+	logs := addLogFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *day == 0 {
+		return errors.New("submit needs -day N")
+	}
+	if *part != 1 && *part != 2 {
+		return fmt.Errorf("invalid -part %d, want 1 or 2", *part)
+	}
+	p, ok := registry.Lookup(*year, *day)
+	if !ok {
+		return fmt.Errorf("no solver registered for %d day %d", *year, *day)
+	}
+	loader, err := input.NewLoader(*inputsDir)
+	if err != nil {
+		return err
+	}
+	if *history == "" {
+		return errors.New("no user cache directory for the submission log, set -history")
+	}
+	submissions, err := aoc.LoadLog(*history)
+	if err != nil {
+		return err
+	}
+	log, err := logs.logger()
+	if err != nil {
+		return err
+	}
+	session, err := aoc.LoadSession(*sessionFile)
+	if err != nil {
+		return err
+	}
+
+	r := &runner.Runner{Loader: loader, Workers: 1, Log: log}
+	res := r.Run(ctx, []runner.Job{{Puzzle: p, Parts: []int{*part}, Variant: input.Variant{Kind: input.Real}}})
+	if err := res[0].Err; err != nil {
+		return fmt.Errorf("day %d part %d: %w", *day, *part, err)
+	}
+	answer := res[0].Answer
+
+	if verdict, ok := submissions.Judged(*year, *day, *part, answer); ok {
+		fmt.Printf("day %d part %d: %d is %s according to %s, not submitted\n", *day, *part, answer, verdict, submissions.Path)
+		return nil
+	}
+	if until := submissions.WaitUntil(); time.Now().Before(until) {
+		left := time.Until(until).Round(time.Second)
+		if !*wait {
+			return fmt.Errorf("the website asked to wait, %v left, use -wait to sleep through it", left)
+		}
+		fmt.Printf("waiting %v before submitting\n", left)
+		timer := time.NewTimer(left)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+
+	client := aoc.NewClient(aoc.BaseURL(*baseURL), session)
+	sent := time.Now()
+	outcome, err := client.Submit(ctx, *year, *day, *part, answer)
+	if err != nil {
+		return err
+	}
+	err = submissions.Append(aoc.Submission{
+		Time: sent, Year: *year, Day: *day, Part: *part, Answer: answer,
+		Verdict: outcome.Verdict, Wait: outcome.Wait, Message: outcome.Message,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("day %d part %d: %d is %s\n", *day, *part, answer, outcome.Verdict)
+	if outcome.Wait > 0 {
+		fmt.Printf("next submission allowed in %v\n", outcome.Wait)
+	}
+	if outcome.Verdict == aoc.Unknown {
+		fmt.Println(outcome.Message)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"adventcode2024/answers"
+	"adventcode2024/input"
+	"adventcode2024/registry"
+	"adventcode2024/runner"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+)
+
+// errRegression is returned by verify when a solver no longer matches a known answer
+var errRegression = errors.New("verify: answers changed")
+
+// verifyCommand runs every registered solver on its real and example inputs
+// and compares the answers with the manifest
+//
+//	advent verify
+//	advent verify -day 6
+//	advent verify -record
+//	advent verify -j 4 -timeout 30s
+func verifyCommand(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("verify", flag.ContinueOnError)
+	year := flags.Int("year", 2024, "puzzle year")
+	day := flags.Int("day", 0, "only verify this day")
+	inputsDir := flags.String("inputs", "", "inputs directory (default $"+input.EnvDir+" or <repo>/inputs)")
+	manifestPath := flags.String("answers", "", "answers manifest (default <inputs>/"+answers.FileName+")")
+	record := flags.Bool("record", false, "record the answers of missing entries in the manifest")
+	workers := flags.Int("j", 1, "number of inputs to solve at the same time")
+	timeout := flags.Duration("timeout", 0, "time limit for each input, 0 for none")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *workers < 1 {
+		return fmt.Errorf("invalid -j %d, want at least 1", *workers)
+	}
+	loader, err := input.NewLoader(*inputsDir)
+	if err != nil {
+		return err
+	}
+	if *manifestPath == "" {
+		*manifestPath = answers.Path(loader.Dir)
+	}
+	manifest, err := answers.Load(*manifestPath)
+	if err != nil {
+		return err
+	}
+
+	var puzzles []registry.Puzzle
+	for _, p := range registry.All() {
+		if p.Year == *year && (*day == 0 || p.Day == *day) {
+			puzzles = append(puzzles, p)
+		}
+	}
+
+	r := &runner.Runner{Loader: loader, Workers: *workers, Timeout: *timeout}
+	checks := answers.Verify(ctx, manifest, r, puzzles)
+	counts := make(map[answers.Status]int)
+	for _, c := range checks {
+		counts[c.Status]++
+		printCheck(c)
+	}
+	fmt.Printf("\n%d pass, %d fail, %d missing\n", counts[answers.Pass], counts[answers.Fail], counts[answers.Missing])
+
+	if *record {
+		if n := answers.Record(manifest, checks); n > 0 {
+			if err := manifest.Save(*manifestPath); err != nil {
+				return err
+			}
+			fmt.Printf("recorded %d answers in %s\n", n, *manifestPath)
+		}
+	}
+
+	if answers.Failed(checks) {
+		return errRegression
+	}
+	return nil
+}
+
+// printCheck prints one line per check
+func printCheck(c answers.Check) {
+	label := fmt.Sprintf("%d day %2d part %d %-6s", c.Year, c.Day, c.Part, c.Input)
+	switch {
+	case c.Status == answers.Pass:
+		fmt.Printf("pass     %s %d\n", label, c.Got)
+	case c.Status == answers.Fail && c.Err != nil:
+		fmt.Printf("FAIL     %s want %d, error: %v\n", label, c.Want, c.Err)
+	case c.Status == answers.Fail:
+		fmt.Printf("FAIL     %s want %d, got %d\n", label, c.Want, c.Got)
+	case c.IsNotImplemented():
+		fmt.Printf("missing  %s not implemented\n", label)
+	case c.Err != nil:
+		fmt.Printf("missing  %s error: %v\n", label, c.Err)
+	default:
+		fmt.Printf("missing  %s got %d\n", label, c.Got)
+	}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"adventcode2024/answers"
+	"adventcode2024/input"
+	"adventcode2024/results"
+	"adventcode2024/watch"
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// watchCommand rebuilds and re-runs a day whenever its source or inputs change.
+// Each run prints the answers next to those of the previous run and checks the
+// examples against the manifest.
+//
+//	advent watch -day 6
+//	advent watch -day 6 -interval 200ms -timeout 10s
+func watchCommand(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("watch", flag.ContinueOnError)
+	year := flags.Int("year", 2024, "puzzle year")
+	day := flags.Int("day", 0, "puzzle day to watch")
+	inputsDir := flags.String("inputs", "", "inputs directory (default $"+input.EnvDir+" or <repo>/inputs)")
+	manifestPath := flags.String("answers", "", "answers manifest (default <inputs>/"+answers.FileName+")")
+	interval := flags.Duration("interval", 500*time.Millisecond, "time between checks for changes")
+	timeout := flags.Duration("timeout", 0, "time limit for each run, 0 for none")
+	logs := addLogFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *day == 0 {
+		return errors.New("watch needs -day N")
+	}
+	if *interval <= 0 {
+		return fmt.Errorf("invalid -interval %v", *interval)
+	}
+	loader, err := input.NewLoader(*inputsDir)
+	if err != nil {
+		return err
+	}
+	if *manifestPath == "" {
+		*manifestPath = answers.Path(loader.Dir)
+	}
+	root, err := moduleRoot()
+	if err != nil {
+		return err
+	}
+	binDir, err := os.MkdirTemp("", "advent-watch")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(binDir)
+
+	w := &watcher{
+		year: *year, day: *day, root: root, loader: loader, manifestPath: *manifestPath,
+		bin:      filepath.Join(binDir, "advent"),
+		runArgs:  append([]string{"-timeout", timeout.String()}, logs.args()...),
+		previous: make(map[string]string),
+	}
+	poller := &watch.Poller{Paths: w.paths, Interval: *interval}
+	snapshot := watch.Take(w.paths())
+	fmt.Printf("watching %d files of day %d, stop with Ctrl-C\n", len(snapshot), *day)
+
+	var changed []string
+	for {
+		w.round(ctx, changed)
+		snapshot, changed, err = poller.Wait(ctx, snapshot)
+		if errors.Is(err, context.Canceled) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// watcher rebuilds and runs one day
+type watcher struct {
+	year, day    int
+	root         string // Module root, where the binary is built
+	loader       *input.Loader
+	manifestPath string            // Read again every round so recorded answers are picked up
+	bin          string            // Path of the built binary
+	runArgs      []string          // Flags passed on to run
+	previous     map[string]string // Answer text of the previous round by part and input
+}
+
+// paths lists the files whose changes trigger a new round: the day's source
+// files, its inputs, the next example that does not exist yet and the manifest
+func (w *watcher) paths() []string {
+	paths := []string{filepath.Join(w.root, "Day", fmt.Sprintf("Day%d.go", w.day))}
+	helpers, _ := filepath.Glob(filepath.Join(w.root, "Day", fmt.Sprintf("Day%d_*.go", w.day)))
+	for _, path := range helpers {
+		if !strings.HasSuffix(path, "_test.go") {
+			paths = append(paths, path)
+		}
+	}
+	paths = append(paths, w.loader.Path(w.day, input.Variant{Kind: input.Real}), w.manifestPath)
+	for n := 1; ; n++ {
+		example := w.loader.Path(w.day, input.Variant{Kind: input.Example, Example: n})
+		paths = append(paths, example)
+		if _, err := os.Stat(example); err != nil {
+			break
+		}
+	}
+	return paths
+}
+
+// round rebuilds the binary, runs the day on every input and prints the answers
+func (w *watcher) round(ctx context.Context, changed []string) {
+	fmt.Println()
+	header := time.Now().Format("15:04:05")
+	if len(changed) > 0 {
+		names := make([]string, len(changed))
+		for i, path := range changed {
+			names[i] = w.relative(path)
+		}
+		header += " changed " + strings.Join(names, ", ")
+	}
+	fmt.Println(header)
+
+	start := time.Now()
+	build := exec.CommandContext(ctx, "go", "build", "-o", w.bin, "./cmd")
+	build.Dir = w.root
+	if out, err := build.CombinedOutput(); err != nil {
+		if ctx.Err() == nil {
+			fmt.Printf("build failed: %v\n%s", err, out)
+		}
+		return
+	}
+	fmt.Printf("built in %v\n", time.Since(start).Round(time.Millisecond))
+
+	manifest, err := answers.Load(w.manifestPath)
+	if err != nil {
+		fmt.Println(err)
+		manifest = answers.NewManifest()
+	}
+
+	var all []results.Result
+	for _, variant := range w.loader.Available(w.day) {
+		res, err := w.run(ctx, variant)
+		if err != nil {
+			if ctx.Err() == nil {
+				fmt.Printf("%s: %v\n", variant, err)
+			}
+			continue
+		}
+		all = append(all, res...)
+	}
+	w.print(manifest, all)
+}
+
+// run runs the built binary on one input and reads its results
+func (w *watcher) run(ctx context.Context, variant input.Variant) ([]results.Result, error) {
+	args := []string{"run", "-year", strconv.Itoa(w.year), "-day", strconv.Itoa(w.day),
+		"-input", variant.String(), "-inputs", w.loader.Dir, "-format", "json"}
+	cmd := exec.CommandContext(ctx, w.bin, append(args, w.runArgs...)...)
+	cmd.Stderr = os.Stderr // Solver logs
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	return results.ReadJSON(bytes.NewReader(out))
+}
+
+// print shows each answer next to the previous one and its check against the
+// manifest, then sums up the checks of the examples
+func (w *watcher) print(manifest *answers.Manifest, all []results.Result) {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PART\tINPUT\tANSWER\tTIME\tPREVIOUS\tCHECK")
+	counts := make(map[answers.Status]int)
+	checks := answers.Compare(manifest, all)
+	for i, r := range all {
+		answer := strconv.FormatInt(r.Answer, 10)
+		if r.Err != nil {
+			answer = "error: " + r.Err.Error()
+		}
+		key := fmt.Sprintf("%d %s", r.Part, r.Input)
+		previous, seen := w.previous[key]
+		switch {
+		case !seen:
+			previous = ""
+		case previous == answer:
+			previous = "same"
+		default:
+			previous = "was " + previous
+		}
+		w.previous[key] = answer
+
+		status, check := checks[i].Status, string(checks[i].Status)
+		if status == answers.Fail {
+			check = fmt.Sprintf("FAIL want %d", checks[i].Want)
+		}
+		if r.Input != "input" {
+			counts[status]++
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\n", r.Part, r.Input, answer, r.Duration.Round(time.Microsecond), previous, check)
+	}
+	tw.Flush()
+	fmt.Printf("examples: %d pass, %d fail, %d missing\n", counts[answers.Pass], counts[answers.Fail], counts[answers.Missing])
+}
+
+// relative returns path relative to the module root when it is inside it
+func (w *watcher) relative(path string) string {
+	if rel, err := filepath.Rel(w.root, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
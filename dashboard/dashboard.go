@@ -0,0 +1,410 @@
+// Package dashboard serves a local web page listing the answers of every day,
+// how they compare with the known answers, their benchmark timings and
+// pictures of the days that can be drawn. The page and its style sheet are
+// embedded so it works offline.
+package dashboard
+
+import (
+	"adventcode2024/answers"
+	"adventcode2024/bench"
+	"adventcode2024/input"
+	"adventcode2024/registry"
+	"adventcode2024/render"
+	"adventcode2024/results"
+	"adventcode2024/runner"
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+//go:embed assets
+var assets embed.FS
+
+// page is the dashboard template, parsed once
+var page = template.Must(template.New("index.html").Funcs(template.FuncMap{
+	"duration": roundDuration,
+}).ParseFS(assets, "assets/index.html"))
+
+// Day is what the dashboard knows about one puzzle
+type Day struct {
+	Puzzle   registry.Puzzle
+	Results  []results.Result    // Both parts on the real input
+	Checks   []answers.Check     // Both parts on every input, against the manifest
+	Timings  []bench.Measurement // From the benchmark report, if any
+	Drawable bool                // The solver implements render.Drawable
+	Updated  time.Time           // When the day was last run
+}
+
+// Part returns the result of a part on the real input, nil when it was not run
+func (d *Day) Part(part int) *results.Result {
+	for i := range d.Results {
+		if d.Results[i].Part == part {
+			return &d.Results[i]
+		}
+	}
+	return nil
+}
+
+// Status sums up the checks: Fail when any failed, Pass when all passed, Missing otherwise
+func (d *Day) Status() answers.Status {
+	status := answers.Pass
+	for _, c := range d.Checks {
+		switch c.Status {
+		case answers.Fail:
+			return answers.Fail
+		case answers.Missing:
+			status = answers.Missing
+		}
+	}
+	if len(d.Checks) == 0 {
+		return answers.Missing
+	}
+	return status
+}
+
+// Timing returns the benchmark of one phase, nil when there is none
+func (d *Day) Timing(phase string) *bench.Measurement {
+	for i := range d.Timings {
+		if d.Timings[i].Phase == phase {
+			return &d.Timings[i]
+		}
+	}
+	return nil
+}
+
+// Server runs the puzzles and serves the dashboard.
+// Days are run by Refresh, the page only shows what was last run.
+type Server struct {
+	Loader   *input.Loader
+	Manifest *answers.Manifest
+	Puzzles  []registry.Puzzle
+	Bench    *bench.Report // Timings shown next to the answers, nil for none
+	Workers  int           // Days run at the same time by Refresh, at least 1
+	Timeout  time.Duration // Limit for solving one day, 0 for no limit
+	Log      *slog.Logger  // Diagnostics of the solvers, nil keeps them silent
+
+	mu   sync.Mutex
+	days map[int]*Day
+}
+
+// Refresh runs the given days, every puzzle when none are given, and keeps
+// their answers and checks for the page
+func (s *Server) Refresh(ctx context.Context, days ...int) error {
+	var puzzles []registry.Puzzle
+	for _, p := range s.Puzzles {
+		if len(days) == 0 || slices.Contains(days, p.Day) {
+			puzzles = append(puzzles, p)
+		}
+	}
+	if len(puzzles) == 0 {
+		return fmt.Errorf("no puzzle for day %v", days)
+	}
+
+	// The real input and every example are solved once, under the timeout,
+	// and the checks are made from the same results
+	realInput := input.Variant{Kind: input.Real}
+	var jobs []runner.Job
+	for _, p := range puzzles {
+		jobs = append(jobs, runner.Job{Puzzle: p, Parts: []int{1, 2}, Variant: realInput})
+		for _, v := range s.Loader.Available(p.Day) {
+			if v != realInput {
+				jobs = append(jobs, runner.Job{Puzzle: p, Parts: []int{1, 2}, Variant: v})
+			}
+		}
+	}
+	r := &runner.Runner{Loader: s.Loader, Workers: s.Workers, Timeout: s.Timeout, Log: s.Log}
+	res := r.Run(ctx, jobs)
+	checks := answers.Compare(s.Manifest, res)
+	if err := ctx.Err(); err != nil {
+		return err // Cancelled runs fail every part, keep what was shown before
+	}
+
+	now := time.Now()
+	updated := make(map[int]*Day, len(puzzles))
+	for _, p := range puzzles {
+		_, drawable := p.New().(render.Drawable)
+		updated[p.Day] = &Day{Puzzle: p, Drawable: drawable, Updated: now}
+	}
+	for _, r := range res {
+		if r.Input == realInput.String() {
+			updated[r.Day].Results = append(updated[r.Day].Results, r)
+		}
+	}
+	for _, c := range checks {
+		updated[c.Day].Checks = append(updated[c.Day].Checks, c)
+	}
+	if s.Bench != nil {
+		for _, m := range s.Bench.Measurements {
+			if d, ok := updated[m.Day]; ok && m.Year == d.Puzzle.Year && m.Input == "input" {
+				d.Timings = append(d.Timings, m)
+			}
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.days == nil {
+		s.days = make(map[int]*Day)
+	}
+	for day, d := range updated {
+		s.days[day] = d
+	}
+	return nil
+}
+
+// Days returns the days run so far in day order
+func (s *Server) Days() []*Day {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	days := make([]*Day, 0, len(s.days))
+	for _, d := range s.days {
+		days = append(days, d)
+	}
+	slices.SortFunc(days, func(a, b *Day) int { return a.Puzzle.Day - b.Puzzle.Day })
+	return days
+}
+
+// Handler returns the routes of the dashboard:
+//
+//	GET  /                         the page
+//	GET  /style.css                its style sheet
+//	GET  /days.json                every day as JSON
+//	POST /days/{day}/run           run a day again
+//	GET  /days/{day}/picture.png   draw a day on its real input, ?scale=N
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.index)
+	mux.Handle("GET /style.css", http.FileServerFS(assetsRoot()))
+	mux.HandleFunc("GET /days.json", s.daysJSON)
+	mux.HandleFunc("POST /days/{day}/run", s.rerun)
+	mux.HandleFunc("GET /days/{day}/picture.png", s.picture)
+	return mux
+}
+
+// assetsRoot returns the embedded assets without their directory prefix
+func assetsRoot() fs.FS {
+	root, err := fs.Sub(assets, "assets")
+	if err != nil {
+		panic(err)
+	}
+	return root
+}
+
+// index renders the page
+func (s *Server) index(w http.ResponseWriter, r *http.Request) {
+	data := struct {
+		Days  []*Day
+		Bench *bench.Report
+	}{s.Days(), s.Bench}
+
+	var sb strings.Builder
+	if err := page.Execute(&sb, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	io.WriteString(w, sb.String())
+}
+
+// daysJSON writes every day as JSON
+func (s *Server) daysJSON(w http.ResponseWriter, r *http.Request) {
+	days := s.Days()
+	records := make([]dayRecord, len(days))
+	for i, d := range days {
+		records[i] = d.record()
+	}
+	writeJSON(w, records)
+}
+
+// rerun runs one day again. Forms are sent back to the page,
+// other clients get the day as JSON. Requests sent by other sites are refused.
+func (s *Server) rerun(w http.ResponseWriter, r *http.Request) {
+	if !sameOrigin(r) {
+		http.Error(w, "cross-origin request refused", http.StatusForbidden)
+		return
+	}
+	day, ok := s.day(w, r)
+	if !ok {
+		return
+	}
+	if err := s.Refresh(r.Context(), day.Day); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if strings.Contains(r.Header.Get("Accept"), "application/json") {
+		for _, d := range s.Days() {
+			if d.Puzzle.Day == day.Day {
+				writeJSON(w, d.record())
+			}
+		}
+		return
+	}
+	http.Redirect(w, r, fmt.Sprintf("/#day-%d", day.Day), http.StatusSeeOther)
+}
+
+// sameOrigin reports whether a request comes from the dashboard's own page.
+// Browsers send Sec-Fetch-Site, older ones an Origin to compare with the Host,
+// and a request with neither does not come from a browser.
+func sameOrigin(r *http.Request) bool {
+	switch r.Header.Get("Sec-Fetch-Site") {
+	case "same-origin", "none":
+		return true
+	case "":
+	default:
+		return false
+	}
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
+
+// picture draws a day that implements render.Drawable on its real input
+func (s *Server) picture(w http.ResponseWriter, r *http.Request) {
+	day, ok := s.day(w, r)
+	if !ok {
+		return
+	}
+	scale := render.DefaultScale
+	if v := r.URL.Query().Get("scale"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 64 {
+			http.Error(w, "scale must be a number from 1 to 64", http.StatusBadRequest)
+			return
+		}
+		scale = n
+	}
+
+	solver := day.New()
+	drawable, ok := solver.(render.Drawable)
+	if !ok {
+		http.Error(w, fmt.Sprintf("day %d cannot be drawn", day.Day), http.StatusNotFound)
+		return
+	}
+	variant := input.Variant{Kind: input.Real}
+	file, err := s.Loader.Open(day.Day, variant)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	err = input.WithFile(solver.Parse(file), s.Loader.Path(day.Day, variant))
+	file.Close()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	img, err := drawable.Draw(r.Context(), scale)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	if err := render.WritePNG(w, img); err != nil {
+		s.log().Warn("writing picture", "day", day.Day, "err", err)
+	}
+}
+
+// day returns the puzzle named by the {day} path value, or writes a 404
+func (s *Server) day(w http.ResponseWriter, r *http.Request) (registry.Puzzle, bool) {
+	n, err := strconv.Atoi(r.PathValue("day"))
+	if err == nil {
+		for _, p := range s.Puzzles {
+			if p.Day == n {
+				return p, true
+			}
+		}
+	}
+	http.Error(w, fmt.Sprintf("no puzzle for day %q", r.PathValue("day")), http.StatusNotFound)
+	return registry.Puzzle{}, false
+}
+
+// log returns the logger of the server, a silent one when none was set
+func (s *Server) log() *slog.Logger {
+	if s.Log == nil {
+		return slog.New(slog.DiscardHandler)
+	}
+	return s.Log
+}
+
+// dayRecord is the JSON form of a Day
+type dayRecord struct {
+	Year     int                 `json:"year"`
+	Day      int                 `json:"day"`
+	Status   answers.Status      `json:"status"`
+	Results  []results.Result    `json:"results"`
+	Checks   []checkRecord       `json:"checks"`
+	Timings  []bench.Measurement `json:"timings,omitempty"`
+	Drawable bool                `json:"drawable"`
+	Updated  time.Time           `json:"updated"`
+}
+
+// checkRecord is the JSON form of an answers.Check
+type checkRecord struct {
+	Part   int            `json:"part"`
+	Input  string         `json:"input"`
+	Status answers.Status `json:"status"`
+	Want   *int64         `json:"want,omitempty"`
+	Got    *int64         `json:"got,omitempty"`
+	Error  string         `json:"error,omitempty"`
+}
+
+// record returns the JSON form of a day
+func (d *Day) record() dayRecord {
+	rec := dayRecord{
+		Year: d.Puzzle.Year, Day: d.Puzzle.Day, Status: d.Status(),
+		Results: d.Results, Timings: d.Timings, Drawable: d.Drawable, Updated: d.Updated,
+		Checks: make([]checkRecord, len(d.Checks)),
+	}
+	for i, c := range d.Checks {
+		cr := checkRecord{Part: c.Part, Input: c.Input, Status: c.Status}
+		if c.Status != answers.Missing {
+			cr.Want = &c.Want
+		}
+		if c.Err != nil {
+			cr.Error = c.Err.Error()
+		} else {
+			cr.Got = &c.Got
+		}
+		rec.Checks[i] = cr
+	}
+	return rec
+}
+
+// writeJSON writes v as indented JSON
+func writeJSON(w http.ResponseWriter, v any) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(append(data, '\n'))
+}
+
+// roundDuration shortens a duration for the page, 1.234567ms reads 1.23ms
+func roundDuration(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return d.Round(10 * time.Millisecond).String()
+	case d >= time.Millisecond:
+		return d.Round(10 * time.Microsecond).String()
+	case d >= time.Microsecond:
+		return d.Round(10 * time.Nanosecond).String()
+	}
+	return d.String()
+}
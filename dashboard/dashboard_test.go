@@ -0,0 +1,254 @@
+package dashboard
+
+import (
+	"adventcode2024/answers"
+	"adventcode2024/input"
+	"adventcode2024/registry"
+	"context"
+	"encoding/json"
+	"image"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// lineSolver answers how many numbers its input holds for part 1 and their sum for part 2
+type lineSolver struct {
+	numbers []int64
+}
+
+func (l *lineSolver) Parse(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	for _, field := range strings.Fields(string(data)) {
+		n, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			return err
+		}
+		l.numbers = append(l.numbers, n)
+	}
+	return nil
+}
+
+func (l *lineSolver) Part1(ctx context.Context) (int64, error) { return int64(len(l.numbers)), nil }
+
+func (l *lineSolver) Part2(ctx context.Context) (int64, error) {
+	var sum int64
+	for _, n := range l.numbers {
+		sum += n
+	}
+	return sum, nil
+}
+
+// drawnSolver is a lineSolver that draws a row of one cell per number
+type drawnSolver struct{ lineSolver }
+
+func (d *drawnSolver) Draw(ctx context.Context, scale int) (image.Image, error) {
+	return image.NewRGBA(image.Rect(0, 0, len(d.numbers)*scale, scale)), nil
+}
+
+// hungSolver never answers part 2 and ignores its context, like a solver stuck in a loop
+type hungSolver struct{ lineSolver }
+
+func (h *hungSolver) Part2(ctx context.Context) (int64, error) { select {} }
+
+// newTestServer returns a server for day 1, which is drawable, and day 2
+// whose known part 2 answer is wrong
+func newTestServer(t *testing.T) *Server {
+	dir := t.TempDir()
+	for name, text := range map[string]string{"input1.txt": "1\n2\n3\n", "test1.txt": "5\n", "input2.txt": "4\n4\n"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(text), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	m := answers.NewManifest()
+	m.Set(answers.Entry{Year: 2024, Day: 1, Part: 1, Input: "input", Answer: 3})
+	m.Set(answers.Entry{Year: 2024, Day: 1, Part: 2, Input: "input", Answer: 6})
+	m.Set(answers.Entry{Year: 2024, Day: 2, Part: 2, Input: "input", Answer: 9})
+
+	return &Server{
+		Loader:   &input.Loader{Dir: dir},
+		Manifest: m,
+		Puzzles: []registry.Puzzle{
+			{Year: 2024, Day: 1, New: func() registry.Solver { return &drawnSolver{} }},
+			{Year: 2024, Day: 2, New: func() registry.Solver { return &lineSolver{} }},
+		},
+	}
+}
+
+// TestDashboard checks the page, the JSON of the days, pictures and re-runs
+func TestDashboard(t *testing.T) {
+	s := newTestServer(t)
+	if err := s.Refresh(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+
+	body := get(t, srv.URL+"/", http.StatusOK)
+	for _, want := range []string{`id="day-1"`, `id="day-2"`, "<span class=\"answer\">6</span>", `href="/days/1/picture.png"`, `action="/days/2/run"`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("page does not contain %s", want)
+		}
+	}
+	if strings.Contains(body, `href="/days/2/picture.png"`) {
+		t.Error("page links to a picture of day 2, which cannot be drawn")
+	}
+	if css := get(t, srv.URL+"/style.css", http.StatusOK); !strings.Contains(css, "table") {
+		t.Error("style.css is not served")
+	}
+
+	var days []dayRecord
+	if err := json.Unmarshal([]byte(get(t, srv.URL+"/days.json", http.StatusOK)), &days); err != nil {
+		t.Fatal(err)
+	}
+	if len(days) != 2 || days[0].Status != answers.Missing || days[1].Status != answers.Fail {
+		t.Fatalf("days.json = %+v, want day 1 missing (no test answer) and day 2 failing", days)
+	}
+	if checks := days[0].Checks; len(checks) != 4 || checks[0].Status != answers.Pass || *checks[0].Got != 3 {
+		t.Errorf("day 1 checks = %+v, want 4 starting with a passing part 1", checks)
+	}
+
+	picture := get(t, srv.URL+"/days/1/picture.png?scale=2", http.StatusOK)
+	img, err := png.Decode(strings.NewReader(picture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 6 || b.Dy() != 2 {
+		t.Errorf("picture is %v, want 6x2", b)
+	}
+	get(t, srv.URL+"/days/2/picture.png", http.StatusNotFound)
+	get(t, srv.URL+"/days/1/picture.png?scale=0", http.StatusBadRequest)
+	get(t, srv.URL+"/days/7/picture.png", http.StatusNotFound)
+}
+
+// TestRerun checks that a re-run picks up a changed input
+func TestRerun(t *testing.T) {
+	s := newTestServer(t)
+	if err := s.Refresh(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+
+	if err := os.WriteFile(s.Loader.Path(2, input.Variant{Kind: input.Real}), []byte("4\n5\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/days/2/run", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	var day dayRecord
+	if err := json.NewDecoder(resp.Body).Decode(&day); err != nil {
+		t.Fatal(err)
+	}
+	if day.Day != 2 || len(day.Checks) != 2 || day.Checks[1].Status != answers.Pass || day.Results[1].Answer != 9 {
+		t.Errorf("re-run = %+v, want day 2 part 2 = 9 passing", day)
+	}
+
+	// A form is sent back to the page
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err = client.Post(srv.URL+"/days/1/run", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if loc := resp.Header.Get("Location"); resp.StatusCode != http.StatusSeeOther || loc != "/#day-1" {
+		t.Errorf("form re-run = %d to %q, want 303 to /#day-1", resp.StatusCode, loc)
+	}
+}
+
+// TestRerunCrossOrigin checks that only the dashboard's own page and clients
+// other than browsers can run a day again
+func TestRerunCrossOrigin(t *testing.T) {
+	s := newTestServer(t)
+	srv := httptest.NewServer(s.Handler())
+	defer srv.Close()
+
+	tests := []struct {
+		name, fetchSite, origin string
+		want                    int
+	}{
+		{"other site", "cross-site", "", http.StatusForbidden},
+		{"other origin", "", "http://evil.example", http.StatusForbidden},
+		{"same origin", "same-origin", srv.URL, http.StatusOK},
+		{"same origin without Sec-Fetch-Site", "", srv.URL, http.StatusOK},
+		{"not a browser", "", "", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, srv.URL+"/days/2/run", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Accept", "application/json")
+			if tt.fetchSite != "" {
+				req.Header.Set("Sec-Fetch-Site", tt.fetchSite)
+			}
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Errorf("status %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
+
+// TestRefreshTimeout checks that a hung solver fails its part on every input
+// once the timeout is up, and does not hold up the refresh
+func TestRefreshTimeout(t *testing.T) {
+	s := newTestServer(t)
+	s.Puzzles[0].New = func() registry.Solver { return &hungSolver{} }
+	s.Timeout = 50 * time.Millisecond
+	if err := s.Refresh(t.Context(), 1); err != nil {
+		t.Fatal(err)
+	}
+	checks := s.Days()[0].Checks
+	if len(checks) != 4 {
+		t.Fatalf("checks = %+v, want both parts on the input and the example", checks)
+	}
+	for _, c := range checks {
+		if timedOut := c.Part == 2; timedOut != (c.Err != nil) {
+			t.Errorf("day 1 part %d %s err = %v, want an error only for the hung part 2", c.Part, c.Input, c.Err)
+		}
+	}
+}
+
+// get fetches url and fails the test unless it answers with status
+func get(t *testing.T, url string, status int) string {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != status {
+		t.Fatalf("GET %s = %d, want %d: %s", url, resp.StatusCode, status, body)
+	}
+	return string(body)
+}
@@ -0,0 +1,77 @@
+// Package gen generates random puzzle inputs for stress testing. Days that
+// implement Generator write valid inputs of any size, and the same seed
+// always gives the same input, so a failing input can be made again from
+// its flags alone.
+package gen
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/rand/v2"
+)
+
+// Options tune a generated input. What they measure is up to each day,
+// which documents them on its Generate method.
+type Options struct {
+	Size    int     // Scale of the input, like the side of a map or the number of machines
+	Density float64 // How full the input is, from 0 to 1, like the share of cells that are obstacles, or DefaultDensity
+}
+
+// DefaultDensity asks for the density of the day's real input, 0 being a valid density of its own
+const DefaultDensity = -1
+
+// Generator is implemented by solvers that can generate their own inputs.
+// Generate writes an input built from opts with rng as its only source of randomness.
+type Generator interface {
+	Generate(w io.Writer, rng *rand.Rand, opts Options) error
+}
+
+// Defaults returns opts with a zero Size or a DefaultDensity replaced by the given ones
+func (opts Options) Defaults(size int, density float64) Options {
+	if opts.Size == 0 {
+		opts.Size = size
+	}
+	if opts.Density == DefaultDensity {
+		opts.Density = density
+	}
+	return opts
+}
+
+// Check reports options no day can generate from
+func (opts Options) Check() error {
+	if opts.Size < 0 {
+		return fmt.Errorf("size %d is negative", opts.Size)
+	}
+	if (opts.Density < 0 || opts.Density > 1) && opts.Density != DefaultDensity {
+		return fmt.Errorf("density %g is not between 0 and 1", opts.Density)
+	}
+	return nil
+}
+
+// Rand returns the random source of a seed
+func Rand(seed uint64) *rand.Rand {
+	return rand.New(rand.NewPCG(seed, seed))
+}
+
+// Write generates an input from seed and opts and writes it to w
+func Write(g Generator, w io.Writer, seed uint64, opts Options) error {
+	if err := opts.Check(); err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(w)
+	if err := g.Generate(bw, Rand(seed), opts); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// Chance reports true with probability p
+func Chance(rng *rand.Rand, p float64) bool {
+	return rng.Float64() < p
+}
+
+// Between returns a random integer from least to most
+func Between(rng *rand.Rand, least, most int) int {
+	return least + rng.IntN(most-least+1)
+}
@@ -0,0 +1,64 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"testing"
+)
+
+// numbers writes Size random numbers below 1000
+type numbers struct{}
+
+func (numbers) Generate(w io.Writer, rng *rand.Rand, opts Options) error {
+	for range opts.Defaults(3, 0).Size {
+		if _, err := fmt.Fprintln(w, rng.IntN(1000)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestWrite(t *testing.T) {
+	generate := func(seed uint64, opts Options) string {
+		var b bytes.Buffer
+		if err := Write(numbers{}, &b, seed, opts); err != nil {
+			t.Fatal(err)
+		}
+		return b.String()
+	}
+	if a, b := generate(7, Options{Size: 20}), generate(7, Options{Size: 20}); a != b {
+		t.Errorf("seed 7 gave %q then %q", a, b)
+	}
+	if a, b := generate(7, Options{Size: 20}), generate(8, Options{Size: 20}); a == b {
+		t.Errorf("seeds 7 and 8 both gave %q", a)
+	}
+	if got := bytes.Count([]byte(generate(1, Options{})), []byte("\n")); got != 3 {
+		t.Errorf("default size gave %d numbers, want 3", got)
+	}
+
+	for _, opts := range []Options{{Size: -1}, {Density: -0.5}, {Density: 1.5}} {
+		if err := Write(numbers{}, io.Discard, 1, opts); err == nil {
+			t.Errorf("Write accepted %+v", opts)
+		}
+	}
+}
+
+func TestDefaults(t *testing.T) {
+	tests := []struct {
+		opts, want Options
+	}{
+		{Options{}, Options{Size: 10, Density: 0}},
+		{Options{Density: DefaultDensity}, Options{Size: 10, Density: 0.5}},
+		{Options{Size: 3, Density: 0.2}, Options{Size: 3, Density: 0.2}},
+	}
+	for _, tt := range tests {
+		if got := tt.opts.Defaults(10, 0.5); got != tt.want {
+			t.Errorf("%+v.Defaults(10, 0.5) = %+v, want %+v", tt.opts, got, tt.want)
+		}
+	}
+	if err := (Options{Density: DefaultDensity}).Check(); err != nil {
+		t.Errorf("Check rejected the default density: %v", err)
+	}
+}
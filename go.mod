@@ -1,5 +1,3 @@
 module adventcode2024
 
 go 1.24.0
-
-replace utils => ./utils
@@ -0,0 +1,209 @@
+// Package input locates and opens puzzle input files.
+// Inputs live in one directory, named input<day>.txt for the real puzzle input
+// and test<day>.txt, test<day>_2.txt, ... for the examples from the puzzle text.
+package input
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Year is the puzzle year of the inputs in the directory, the file names do not carry it
+const Year = 2024
+
+// EnvDir is the environment variable that overrides the inputs directory
+const EnvDir = "ADVENT_INPUTS"
+
+// ErrNoDir is returned when no inputs directory can be found
+var ErrNoDir = errors.New("inputs directory not found")
+
+// Kind says where a Variant reads its input from
+type Kind int
+
+const (
+	Real    Kind = iota // The real puzzle input, input<day>.txt
+	Example             // An example from the puzzle text, test<day>.txt
+	Path                // An explicit file path
+	Stdin               // Standard input
+)
+
+// Variant selects which input of a day to read
+type Variant struct {
+	Kind    Kind
+	Example int    // Example number for Kind Example, starting at 1
+	Path    string // File path for Kind Path
+}
+
+// ParseVariant parses a variant as given on the command line:
+//   - "input" or "real": the real puzzle input
+//   - "test" or "example": the first example
+//   - "test2", "example2", ...: later examples
+//   - "-": standard input
+//   - anything containing a path separator or ending in ".txt": that file
+func ParseVariant(s string) (Variant, error) {
+	switch {
+	case s == "input" || s == "real":
+		return Variant{Kind: Real}, nil
+	case s == "-":
+		return Variant{Kind: Stdin}, nil
+	case strings.ContainsRune(s, '/') || strings.ContainsRune(s, filepath.Separator) || strings.HasSuffix(s, ".txt"):
+		return Variant{Kind: Path, Path: s}, nil
+	}
+
+	for _, prefix := range []string{"test", "example"} {
+		if !strings.HasPrefix(s, prefix) {
+			continue
+		}
+		number := strings.TrimPrefix(s, prefix)
+		if number == "" {
+			return Variant{Kind: Example, Example: 1}, nil
+		}
+		n, err := strconv.Atoi(number)
+		if err != nil || n < 1 {
+			return Variant{}, fmt.Errorf("invalid example number in %q", s)
+		}
+		return Variant{Kind: Example, Example: n}, nil
+	}
+	return Variant{}, fmt.Errorf("unknown input variant %q, want input, test, testN, a path or -", s)
+}
+
+// String returns the variant in the form ParseVariant accepts
+func (v Variant) String() string {
+	switch v.Kind {
+	case Real:
+		return "input"
+	case Example:
+		if v.Example <= 1 {
+			return "test"
+		}
+		return fmt.Sprintf("test%d", v.Example)
+	case Path:
+		return v.Path
+	case Stdin:
+		return "-"
+	}
+	return fmt.Sprintf("Variant(%d)", v.Kind)
+}
+
+// Loader opens the inputs of any day from one inputs directory
+type Loader struct {
+	Dir   string    // Inputs directory
+	Stdin io.Reader // Read for Kind Stdin, os.Stdin when nil
+}
+
+// NewLoader returns a Loader for the inputs directory found by FindDir
+func NewLoader(dir string) (*Loader, error) {
+	dir, err := FindDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Loader{Dir: dir}, nil
+}
+
+// FindDir resolves the inputs directory. In order of preference:
+//  1. dir, when not empty (usually from a command line flag)
+//  2. the directory named by the ADVENT_INPUTS environment variable
+//  3. the inputs directory at the repository root, found by walking up
+//     from the working directory to the first directory holding go.mod
+func FindDir(dir string) (string, error) {
+	if dir == "" {
+		dir = os.Getenv(EnvDir)
+	}
+	if dir != "" {
+		if !isDir(dir) {
+			return "", fmt.Errorf("%w: %s is not a directory", ErrNoDir, dir)
+		}
+		return dir, nil
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	for current := wd; ; current = filepath.Dir(current) {
+		if isFile(filepath.Join(current, "go.mod")) && isDir(filepath.Join(current, "inputs")) {
+			return filepath.Join(current, "inputs"), nil
+		}
+		if filepath.Dir(current) == current {
+			break
+		}
+	}
+	return "", fmt.Errorf("%w: set -inputs or %s, or run inside the repository", ErrNoDir, EnvDir)
+}
+
+// Path returns the file a variant of a day is read from.
+// Stdin has no path and returns "-".
+func (l *Loader) Path(day int, v Variant) string {
+	switch v.Kind {
+	case Real:
+		return filepath.Join(l.Dir, fmt.Sprintf("input%d.txt", day))
+	case Example:
+		if v.Example <= 1 {
+			return filepath.Join(l.Dir, fmt.Sprintf("test%d.txt", day))
+		}
+		return filepath.Join(l.Dir, fmt.Sprintf("test%d_%d.txt", day, v.Example))
+	case Path:
+		return v.Path
+	}
+	return "-"
+}
+
+// Open opens a variant of a day for reading.
+// The caller must close the returned reader.
+func (l *Loader) Open(day int, v Variant) (io.ReadCloser, error) {
+	if v.Kind == Stdin {
+		stdin := l.Stdin
+		if stdin == nil {
+			stdin = os.Stdin
+		}
+		return io.NopCloser(stdin), nil
+	}
+
+	file, err := os.Open(l.Path(day, v))
+	if err != nil {
+		return nil, fmt.Errorf("day %d %s input: %w", day, v, err)
+	}
+	return file, nil
+}
+
+// Available returns the variants of a day that have a non-empty file,
+// the real input first and then the examples in order
+func (l *Loader) Available(day int) []Variant {
+	var variants []Variant
+	if hasContent(l.Path(day, Variant{Kind: Real})) {
+		variants = append(variants, Variant{Kind: Real})
+	}
+	for n := 1; ; n++ {
+		example := Variant{Kind: Example, Example: n}
+		if !isFile(l.Path(day, example)) {
+			break
+		}
+		if hasContent(l.Path(day, example)) {
+			variants = append(variants, example)
+		}
+	}
+	return variants
+}
+
+// hasContent reports whether path is a regular file that is not empty
+func hasContent(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular() && info.Size() > 0
+}
+
+// isDir reports whether path exists and is a directory
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// isFile reports whether path exists and is a regular file
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
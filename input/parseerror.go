@@ -0,0 +1,144 @@
+package input
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ParseError reports input that does not have the format a parser expects.
+// Line and Column are 1-based, zero means unknown.
+type ParseError struct {
+	File     string // Input file, filled in by whoever opened it
+	Line     int    // Line of the offending text
+	Column   int    // Column of the offending text, in bytes
+	Text     string // The offending text, empty at the end of a line or input
+	Expected string // What the parser expected instead, e.g. "an integer"
+}
+
+// Error formats the error as file:line:column: found "text", want expected
+func (e *ParseError) Error() string {
+	var sb strings.Builder
+	for _, pos := range []struct {
+		set   bool
+		value string
+	}{
+		{e.File != "", e.File},
+		{e.Line > 0, strconv.Itoa(e.Line)},
+		{e.Column > 0, strconv.Itoa(e.Column)},
+	} {
+		if pos.set {
+			sb.WriteString(pos.value + ":")
+		}
+	}
+	if sb.Len() > 0 {
+		sb.WriteString(" ")
+	}
+	if e.Text == "" {
+		sb.WriteString("found nothing")
+	} else {
+		fmt.Fprintf(&sb, "found %q", e.Text)
+	}
+	sb.WriteString(", want " + e.Expected)
+	return sb.String()
+}
+
+// WithFile sets the file of the ParseError in err's chain, unless it already has one.
+// Other errors are returned unchanged. Call it before wrapping the error further,
+// wrappers like fmt.Errorf format the message when they are created.
+func WithFile(err error, file string) error {
+	var pe *ParseError
+	if errors.As(err, &pe) && pe.File == "" {
+		pe.File = file
+	}
+	return err
+}
+
+// WithLine sets the line of the ParseError in err's chain, unless it already has one.
+// Parsers of a single line use it to leave the line number to their caller.
+func WithLine(err error, line int) error {
+	var pe *ParseError
+	if errors.As(err, &pe) && pe.Line == 0 {
+		pe.Line = line
+	}
+	return err
+}
+
+// Field is a piece of a line together with the column it starts at
+type Field struct {
+	Text   string
+	Column int // 1-based column of the first byte of Text
+}
+
+// Line returns a whole line as a Field starting at column 1
+func Line(s string) Field {
+	return Field{Text: s, Column: 1}
+}
+
+// Fields splits s around runs of white space, like strings.Fields
+func Fields(s string) []Field {
+	return Line(s).Fields()
+}
+
+// Fields splits the field around runs of white space, like strings.Fields
+func (f Field) Fields() []Field {
+	var fields []Field
+	start := -1
+	for i, r := range f.Text {
+		switch {
+		case unicode.IsSpace(r) && start >= 0:
+			fields = append(fields, Field{Text: f.Text[start:i], Column: f.Column + start})
+			start = -1
+		case !unicode.IsSpace(r) && start < 0:
+			start = i
+		}
+	}
+	if start >= 0 {
+		fields = append(fields, Field{Text: f.Text[start:], Column: f.Column + start})
+	}
+	return fields
+}
+
+// Split splits the field around every sep, like strings.Split
+func (f Field) Split(sep string) []Field {
+	var fields []Field
+	offset := 0
+	for _, part := range strings.Split(f.Text, sep) {
+		fields = append(fields, Field{Text: part, Column: f.Column + offset})
+		offset += len(part) + len(sep)
+	}
+	return fields
+}
+
+// CutPrefix returns the field without prefix and whether it had the prefix
+func (f Field) CutPrefix(prefix string) (Field, bool) {
+	if !strings.HasPrefix(f.Text, prefix) {
+		return f, false
+	}
+	return Field{Text: f.Text[len(prefix):], Column: f.Column + len(prefix)}, true
+}
+
+// Int parses the field as a base 10 int
+func (f Field) Int() (int, error) {
+	n, err := strconv.Atoi(f.Text)
+	if err != nil {
+		return 0, f.Error("an integer")
+	}
+	return n, nil
+}
+
+// Int64 parses the field as a base 10 int64
+func (f Field) Int64() (int64, error) {
+	n, err := strconv.ParseInt(f.Text, 10, 64)
+	if err != nil {
+		return 0, f.Error("an integer")
+	}
+	return n, nil
+}
+
+// Error returns a ParseError pointing at the field
+func (f Field) Error(expected string) *ParseError {
+	return &ParseError{Column: f.Column, Text: f.Text, Expected: expected}
+}
@@ -0,0 +1,46 @@
+package input
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// TestParseErrorMessage checks the file:line:column prefix and WithFile/WithLine
+func TestParseErrorMessage(t *testing.T) {
+	err := error(&ParseError{Column: 4, Text: "x1", Expected: "an integer"})
+	if got, want := err.Error(), `4: found "x1", want an integer`; got != want {
+		t.Errorf("Error() = %s, want %s", got, want)
+	}
+
+	// The position must be filled in before wrapping, fmt.Errorf formats eagerly
+	err = fmt.Errorf("parse: %w", WithFile(WithLine(err, 3), "input1.txt"))
+	if got, want := err.Error(), `parse: input1.txt:3:4: found "x1", want an integer`; got != want {
+		t.Errorf("Error() = %s, want %s", got, want)
+	}
+
+	// A position that is already known is kept
+	err = WithLine(err, 9)
+	var pe *ParseError
+	if !errors.As(err, &pe) || pe.Line != 3 {
+		t.Errorf("WithLine overwrote line 3: %v", err)
+	}
+}
+
+// TestFieldColumns checks the columns of split fields
+func TestFieldColumns(t *testing.T) {
+	fields := Fields("  12   x3 4")
+	want := []Field{{"12", 3}, {"x3", 8}, {"4", 11}}
+	if fmt.Sprint(fields) != fmt.Sprint(want) {
+		t.Errorf("Fields = %v, want %v", fields, want)
+	}
+
+	rest, _ := Line("p=0,-4").CutPrefix("p=")
+	parts := rest.Split(",")
+	if parts[1].Column != 5 {
+		t.Errorf("column of %q = %d, want 5", parts[1].Text, parts[1].Column)
+	}
+	if _, err := parts[1].Int(); err != nil {
+		t.Error(err)
+	}
+}
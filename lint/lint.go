@@ -0,0 +1,157 @@
+// Package lint checks the structure of puzzle inputs before they are solved.
+// A parser stops at the first problem it meets; a linter reads the whole input
+// and reports every problem with its shape, the characters used, required
+// markers and the ranges of numbers, each as an *input.ParseError.
+package lint
+
+import (
+	"adventcode2024/input"
+	"adventcode2024/utils/parse"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"unicode/utf8"
+)
+
+// Linter is implemented by solvers that can check their input
+type Linter interface {
+	Lint(r io.Reader) (Problems, error)
+}
+
+// Problems lists the problems of one input
+type Problems []*input.ParseError
+
+// Add records a problem with a field of a line
+func (p *Problems) Add(line int, f input.Field, expected string) {
+	pe := f.Error(expected)
+	pe.Line = line
+	*p = append(*p, pe)
+}
+
+// AddError records an error of a parser helper on a line. A *input.ParseError
+// keeps its column and text, other errors only their message.
+func (p *Problems) AddError(line int, err error) {
+	var pe *input.ParseError
+	if !errors.As(err, &pe) {
+		pe = &input.ParseError{Expected: err.Error()}
+	}
+	input.WithLine(pe, line)
+	*p = append(*p, pe)
+}
+
+// AddInput records a problem with the input as a whole, such as a missing marker
+func (p *Problems) AddInput(expected string) {
+	*p = append(*p, &input.ParseError{Expected: expected})
+}
+
+// WithFile sets the file of every problem
+func (p Problems) WithFile(file string) {
+	for _, pe := range p {
+		pe.File = file
+	}
+}
+
+// Sort orders the problems by position, problems of the whole input first
+func (p Problems) Sort() {
+	slices.SortStableFunc(p, func(a, b *input.ParseError) int {
+		return cmp.Or(cmp.Compare(a.Line, b.Line), cmp.Compare(a.Column, b.Column))
+	})
+}
+
+// NotEmpty reports an input without lines, it returns whether there are any
+func NotEmpty(p *Problems, lines []parse.Line, expected string) bool {
+	if len(lines) == 0 {
+		*p = append(*p, &input.ParseError{Line: 1, Expected: expected})
+		return false
+	}
+	return true
+}
+
+// SingleLine checks that an input is one line and returns it
+func SingleLine(p *Problems, lines []parse.Line, expected string) (parse.Line, bool) {
+	if !NotEmpty(p, lines, expected) {
+		return parse.Line{}, false
+	}
+	for _, l := range lines[1:] {
+		if l.Text != "" {
+			p.Add(l.Number, l.Field, "nothing after the first line")
+		}
+	}
+	return lines[0], true
+}
+
+// Grid checks that lines form a rectangular map whose runes are all accepted
+// by accept. Each row of another width is one problem, each rune that is
+// not accepted is one problem.
+func Grid(p *Problems, lines []parse.Line, accept func(r rune) bool, expected string) {
+	if !NotEmpty(p, lines, "a map") {
+		return
+	}
+	width := utf8.RuneCountInString(lines[0].Text)
+	for _, l := range lines {
+		if n := utf8.RuneCountInString(l.Text); n != width {
+			p.Add(l.Number, l.Field, fmt.Sprintf("a row of %d characters like the first row", width))
+		}
+		for col, r := range l.Text {
+			if !accept(r) {
+				p.Add(l.Number, input.Field{Text: string(r), Column: col + 1}, expected)
+			}
+		}
+	}
+}
+
+// Accepts turns a grid.Parse mapper into the accept function of Grid, so the
+// linter and the parser of a day agree on the characters of the map
+func Accepts[T any](mapper func(r rune) (T, bool)) func(r rune) bool {
+	return func(r rune) bool {
+		_, ok := mapper(r)
+		return ok
+	}
+}
+
+// Markers checks that a map holds marker between least and most times.
+// Markers past most are reported where they are, too few as a problem of the
+// whole input.
+func Markers(p *Problems, lines []parse.Line, marker rune, least, most int, expected string) {
+	found := 0
+	for _, l := range lines {
+		for col, r := range l.Text {
+			if r != marker {
+				continue
+			}
+			found++
+			if found > most {
+				p.Add(l.Number, input.Field{Text: string(r), Column: col + 1}, expected)
+			}
+		}
+	}
+	if found < least {
+		p.AddInput(expected)
+	}
+}
+
+// Int parses a field as an integer from least to most, reporting it otherwise
+func Int(p *Problems, line int, f input.Field, least, most int) (int, bool) {
+	n, err := f.Int()
+	if err != nil {
+		p.Add(line, f, "an integer")
+		return 0, false
+	}
+	if n < least || n > most {
+		p.Add(line, f, Range(least, most))
+		return n, false
+	}
+	return n, true
+}
+
+// Range describes the integers from least to most for a problem,
+// most is math.MaxInt for numbers without an upper bound
+func Range(least, most int) string {
+	if most == math.MaxInt {
+		return fmt.Sprintf("an integer of at least %d", least)
+	}
+	return fmt.Sprintf("an integer from %d to %d", least, most)
+}
@@ -0,0 +1,88 @@
+package lint
+
+import (
+	"adventcode2024/input"
+	"adventcode2024/utils/parse"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+// positions returns line:column:text of each problem
+func positions(p Problems) string {
+	var out []string
+	for _, pe := range p {
+		out = append(out, fmt.Sprintf("%d:%d:%q", pe.Line, pe.Column, pe.Text))
+	}
+	return strings.Join(out, " ")
+}
+
+func TestGrid(t *testing.T) {
+	lines, _ := parse.Lines(strings.NewReader("..#\n.x\n#.y\n"))
+	var p Problems
+	Grid(&p, lines, func(r rune) bool { return r == '.' || r == '#' }, "'.' or '#'")
+	if got, want := positions(p), `2:1:".x" 2:2:"x" 3:3:"y"`; got != want {
+		t.Errorf("problems %s, want %s", got, want)
+	}
+
+	p = nil
+	Grid(&p, nil, func(r rune) bool { return true }, "anything")
+	if got, want := positions(p), `1:0:""`; got != want {
+		t.Errorf("empty map: problems %s, want %s", got, want)
+	}
+}
+
+func TestMarkers(t *testing.T) {
+	lines, _ := parse.Lines(strings.NewReader("^.^\n..^\n"))
+	var p Problems
+	Markers(&p, lines, '^', 1, 1, "one guard")
+	if got, want := positions(p), `1:3:"^" 2:3:"^"`; got != want {
+		t.Errorf("problems %s, want %s", got, want)
+	}
+
+	lines, _ = parse.Lines(strings.NewReader("...\n"))
+	p = nil
+	Markers(&p, lines, '^', 1, 1, "one guard")
+	if len(p) != 1 || p[0].Line != 0 || p[0].Expected != "one guard" {
+		t.Errorf("missing marker: problems %v", p)
+	}
+}
+
+func TestInt(t *testing.T) {
+	tests := []struct {
+		text        string
+		least, most int
+		ok          bool
+		expected    string
+	}{
+		{"42", 0, 100, true, ""},
+		{"x", 0, 100, false, "an integer"},
+		{"101", 0, 100, false, "an integer from 0 to 100"},
+		{"-1", 0, math.MaxInt, false, "an integer of at least 0"},
+	}
+	for _, tt := range tests {
+		var p Problems
+		_, ok := Int(&p, 3, input.Field{Text: tt.text, Column: 5}, tt.least, tt.most)
+		if ok != tt.ok || ok != (len(p) == 0) {
+			t.Errorf("Int(%q) = %v with %v", tt.text, ok, p)
+			continue
+		}
+		if !ok && (p[0].Line != 3 || p[0].Column != 5 || p[0].Expected != tt.expected) {
+			t.Errorf("Int(%q) problem %v, want 3:5 %s", tt.text, p[0], tt.expected)
+		}
+	}
+}
+
+func TestSort(t *testing.T) {
+	p := Problems{
+		{Line: 2, Column: 1, Text: "b"},
+		{Line: 1, Column: 5, Text: "a"},
+		{Expected: "a guard"},
+		{Line: 1, Column: 2, Text: "c"},
+	}
+	p.Sort()
+	if got, want := positions(p), `0:0:"" 1:2:"c" 1:5:"a" 2:1:"b"`; got != want {
+		t.Errorf("sorted %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,196 @@
+// Package logging builds the slog logger handed to solvers.
+// Every solver logs through a child logger carrying its day, so the level
+// can be raised for one day without drowning the output of the others.
+package logging
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DayKey is the attribute holding the day of a solver's logger
+const DayKey = "day"
+
+// Levels is the minimum level logged for each day
+type Levels struct {
+	Default slog.Level         // Level of days without their own
+	Days    map[int]slog.Level // Level by day
+}
+
+// For returns the level of a day
+func (l Levels) For(day int) slog.Level {
+	if level, ok := l.Days[day]; ok {
+		return level
+	}
+	return l.Default
+}
+
+// ParseLevels reads a comma separated list of levels, each either a level
+// for every day or dayN=level for one day, e.g. "info,day6=debug".
+// The default level of an empty list is base.
+func ParseLevels(spec string, base slog.Level) (Levels, error) {
+	levels := Levels{Default: base, Days: make(map[int]slog.Level)}
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, value, forDay := strings.Cut(item, "=")
+		if !forDay {
+			value = name
+		}
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(value)); err != nil {
+			return Levels{}, fmt.Errorf("invalid log level %q, want debug, info, warn or error", value)
+		}
+		if !forDay {
+			levels.Default = level
+			continue
+		}
+		day, err := strconv.Atoi(strings.TrimPrefix(name, "day"))
+		if err != nil || day < 1 || day > 25 {
+			return Levels{}, fmt.Errorf("invalid day %q in -log, want day1 to day25", name)
+		}
+		levels.Days[day] = level
+	}
+	return levels, nil
+}
+
+// New returns a logger writing text lines to w, filtered by the levels of each day.
+// Records outside any day's logger use the default level.
+func New(w io.Writer, levels Levels) *slog.Logger {
+	return slog.New(&handler{
+		out:    &output{w: w},
+		levels: levels,
+		level:  levels.Default,
+	})
+}
+
+// output serialises writes from the solvers running at the same time
+type output struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// handler formats records as "LEVEL day=6 message key=value" lines.
+// Multi-line string values, such as rendered grids, follow on their own lines.
+type handler struct {
+	out    *output
+	levels Levels
+	level  slog.Level  // Level of the day this handler is for
+	attrs  []slog.Attr // Attributes from With, already prefixed with their groups
+	group  string      // Prefix of the current group, "" or "name."
+}
+
+// Enabled reports whether the handler's day logs at level
+func (h *handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+// Handle writes one record
+func (h *handler) Handle(_ context.Context, r slog.Record) error {
+	var line, blocks bytes.Buffer
+	line.WriteString(r.Level.String())
+
+	write := func(a slog.Attr) {
+		value := a.Value.Resolve().String()
+		if strings.Contains(value, "\n") {
+			fmt.Fprintf(&blocks, "%s:\n%s", a.Key, value)
+			if !strings.HasSuffix(value, "\n") {
+				blocks.WriteByte('\n')
+			}
+			return
+		}
+		fmt.Fprintf(&line, " %s=%s", a.Key, quote(value))
+	}
+	for _, a := range h.attrs {
+		write(a)
+	}
+	line.WriteString(" " + r.Message)
+	r.Attrs(func(a slog.Attr) bool {
+		for _, flat := range flatten(h.group, a) {
+			write(flat)
+		}
+		return true
+	})
+	line.WriteByte('\n')
+
+	h.out.mu.Lock()
+	defer h.out.mu.Unlock()
+	if _, err := h.out.w.Write(line.Bytes()); err != nil {
+		return err
+	}
+	_, err := h.out.w.Write(blocks.Bytes())
+	return err
+}
+
+// WithAttrs returns a handler adding attrs to every record.
+// A day attribute switches the handler to that day's level.
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	child := *h
+	child.attrs = append([]slog.Attr(nil), h.attrs...)
+	for _, a := range attrs {
+		if a.Key == DayKey && h.group == "" {
+			child.level = h.levels.For(int(a.Value.Resolve().Int64()))
+		}
+		child.attrs = append(child.attrs, flatten(h.group, a)...)
+	}
+	return &child
+}
+
+// WithGroup returns a handler prefixing the keys of later attributes with name
+func (h *handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	child := *h
+	child.group = h.group + name + "."
+	return &child
+}
+
+// flatten returns a with its key prefixed, and the members of a group attribute as separate attributes
+func flatten(prefix string, a slog.Attr) []slog.Attr {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() != slog.KindGroup {
+		if a.Key == "" {
+			return nil
+		}
+		return []slog.Attr{{Key: prefix + a.Key, Value: a.Value}}
+	}
+	if a.Key != "" {
+		prefix += a.Key + "."
+	}
+	var flat []slog.Attr
+	for _, member := range a.Value.Group() {
+		flat = append(flat, flatten(prefix, member)...)
+	}
+	return flat
+}
+
+// quote quotes a value that would otherwise be ambiguous on a key=value line
+func quote(value string) string {
+	if value == "" || strings.ContainsAny(value, " =\"\t") {
+		return strconv.Quote(value)
+	}
+	return value
+}
+
+// lazy is a log value computed only when the record is written
+type lazy func() any
+
+// LogValue computes the value
+func (f lazy) LogValue() slog.Value {
+	return slog.AnyValue(f())
+}
+
+// Lazy defers an expensive value, such as a rendered grid, until a handler
+// actually writes it, so disabled debug records cost nothing to build
+func Lazy(f func() any) slog.LogValuer {
+	return lazy(f)
+}
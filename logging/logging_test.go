@@ -0,0 +1,75 @@
+package logging
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+)
+
+// TestParseLevels checks the default level and the per-day levels of -log
+func TestParseLevels(t *testing.T) {
+	levels, err := ParseLevels("info, day6=debug,7=error", slog.LevelWarn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for day, want := range map[int]slog.Level{1: slog.LevelInfo, 6: slog.LevelDebug, 7: slog.LevelError} {
+		if got := levels.For(day); got != want {
+			t.Errorf("For(%d) = %v, want %v", day, got, want)
+		}
+	}
+
+	if levels, _ := ParseLevels("", slog.LevelWarn); levels.For(3) != slog.LevelWarn {
+		t.Errorf("empty spec level = %v, want the base level", levels.For(3))
+	}
+	for _, bad := range []string{"loud", "day6=loud", "day26=debug", "dayx=info"} {
+		if _, err := ParseLevels(bad, slog.LevelWarn); err == nil {
+			t.Errorf("ParseLevels(%q) accepted", bad)
+		}
+	}
+}
+
+// TestDayFiltering checks that each day's logger uses its own level and the output format
+func TestDayFiltering(t *testing.T) {
+	var out bytes.Buffer
+	log := New(&out, Levels{Default: slog.LevelWarn, Days: map[int]slog.Level{6: slog.LevelDebug}})
+
+	day6 := log.With(DayKey, 6)
+	day7 := log.With(DayKey, 7)
+	day6.Debug("guard moved", "pos", "3,4", "direction", "N")
+	day7.Debug("hidden")
+	day7.Info("hidden")
+	day7.Warn("odd input", "line", 3)
+	day6.WithGroup("walk").Info("done", "steps", 41)
+
+	want := "DEBUG day=6 guard moved pos=3,4 direction=N\n" +
+		"WARN day=7 odd input line=3\n" +
+		"INFO day=6 done walk.steps=41\n"
+	if out.String() != want {
+		t.Errorf("output =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+// TestMultiLine checks that multi-line values follow the record and that Lazy values are only built when written
+func TestMultiLine(t *testing.T) {
+	var out bytes.Buffer
+	log := New(&out, Levels{Default: slog.LevelInfo}).With(DayKey, 4)
+
+	built := 0
+	grid := Lazy(func() any {
+		built++
+		return "XMAS\nMMAS\n"
+	})
+	log.Debug("grid", "cells", grid)
+	if built != 0 {
+		t.Errorf("disabled record built its lazy value %d times", built)
+	}
+	log.Info("grid", "cells", grid, "rows", 2)
+
+	want := "INFO day=4 grid rows=2\ncells:\nXMAS\nMMAS\n"
+	if out.String() != want {
+		t.Errorf("output =\n%q\nwant\n%q", out.String(), want)
+	}
+	if built != 1 {
+		t.Errorf("lazy value built %d times, want 1", built)
+	}
+}
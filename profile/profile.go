@@ -0,0 +1,182 @@
+// Package profile records CPU, memory, blocking and execution-trace profiles
+// around one piece of work, usually one part of one day, and summarises them
+// with go tool pprof.
+package profile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+	"strconv"
+	"strings"
+)
+
+// Options selects the profiles to record
+type Options struct {
+	CPU   bool
+	Mem   bool // Heap allocations made while recording, against a baseline written when the session starts
+	Block bool // Time spent blocked on channels and locks
+	Trace bool // Execution trace, read with go tool trace
+	Dir   string
+}
+
+// Enabled reports whether any profile is selected
+func (o Options) Enabled() bool {
+	return o.CPU || o.Mem || o.Block || o.Trace
+}
+
+// Session is a recording started by Start
+type Session struct {
+	opts  Options
+	name  string
+	cpu   *os.File
+	trace *os.File
+	base  string // Allocations before the session started, the baseline of the memory profile
+}
+
+// Suffixes of the memory profile and of its baseline
+const (
+	memSuffix  = "mem.pprof"
+	baseSuffix = "mem.base.pprof"
+)
+
+// Start starts recording the selected profiles. Files are named after name,
+// day6-part2.cpu.pprof, day6-part2.trace and so on. The memory profile counts
+// every allocation since the program started, so its baseline is written here
+// and the allocations of the session are the difference of the two.
+func Start(opts Options, name string) (*Session, error) {
+	s := &Session{opts: opts, name: name}
+	if err := s.start(); err != nil {
+		s.stopRecording()
+		return nil, err
+	}
+	return s, nil
+}
+
+// start starts each selected recording, stopRecording undoes what it started
+func (s *Session) start() error {
+	var err error
+	if s.opts.Mem {
+		runtime.GC() // Settle the allocations made so far into the baseline
+		var files []string
+		if err := s.write(baseSuffix, "allocs", &files); err != nil {
+			return err
+		}
+		s.base = files[0]
+	}
+	if s.opts.Block {
+		runtime.SetBlockProfileRate(1)
+	}
+	if s.opts.CPU {
+		if s.cpu, err = os.Create(s.path("cpu.pprof")); err != nil {
+			return err
+		}
+		if err := pprof.StartCPUProfile(s.cpu); err != nil {
+			s.cpu.Close()
+			s.cpu = nil
+			return err
+		}
+	}
+	if s.opts.Trace {
+		if s.trace, err = os.Create(s.path("trace")); err != nil {
+			return err
+		}
+		if err := trace.Start(s.trace); err != nil {
+			s.trace.Close()
+			s.trace = nil
+			return err
+		}
+	}
+	return nil
+}
+
+// Stop stops recording and writes every profile. It returns the files written,
+// pprof profiles first, the memory profile followed by its baseline.
+func (s *Session) Stop() ([]string, error) {
+	errs := []error{s.stopRecording()}
+	var files []string
+	if s.opts.CPU {
+		files = append(files, s.cpu.Name())
+	}
+	if s.opts.Mem {
+		runtime.GC() // Count what is still live up to this point
+		errs = append(errs, s.write(memSuffix, "allocs", &files))
+		files = append(files, s.base)
+	}
+	if s.opts.Block {
+		errs = append(errs, s.write("block.pprof", "block", &files))
+	}
+	if s.opts.Trace {
+		files = append(files, s.trace.Name())
+	}
+	return files, errors.Join(errs...)
+}
+
+// stopRecording stops the block profile, the CPU profile and the trace and closes their files
+func (s *Session) stopRecording() error {
+	runtime.SetBlockProfileRate(0)
+	var errs []error
+	if s.cpu != nil {
+		pprof.StopCPUProfile()
+		errs = append(errs, s.cpu.Close())
+	}
+	if s.trace != nil {
+		trace.Stop()
+		errs = append(errs, s.trace.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// write writes the named runtime profile and adds its file to files
+func (s *Session) write(suffix, profile string, files *[]string) error {
+	f, err := os.Create(s.path(suffix))
+	if err != nil {
+		return err
+	}
+	err = pprof.Lookup(profile).WriteTo(f, 0)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("%s profile: %w", profile, err)
+	}
+	*files = append(*files, f.Name())
+	return nil
+}
+
+// path returns the file of one profile
+func (s *Session) path(suffix string) string {
+	return filepath.Join(s.opts.Dir, s.name+"."+suffix)
+}
+
+// IsPprof reports whether a file written by Stop can be read by go tool pprof
+func IsPprof(path string) bool {
+	return filepath.Ext(path) == ".pprof"
+}
+
+// IsBaseline reports whether a file written by Stop is the baseline of a memory profile
+func IsBaseline(path string) bool {
+	return strings.HasSuffix(path, "."+baseSuffix)
+}
+
+// Top returns the n functions with the largest flat cost in a pprof profile,
+// as printed by go tool pprof -top. A memory profile is shown against its
+// baseline, so only the allocations of its session count.
+func Top(ctx context.Context, path string, n int) (string, error) {
+	args := []string{"tool", "pprof", "-top", "-nodecount=" + strconv.Itoa(n)}
+	if mem, ok := strings.CutSuffix(path, "."+memSuffix); ok {
+		args = append(args, "-base", mem+"."+baseSuffix)
+	}
+	cmd := exec.CommandContext(ctx, "go", append(args, path)...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("go tool pprof %s: %w\n%s", path, err, out)
+	}
+	return string(out), nil
+}
@@ -0,0 +1,93 @@
+package profile
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// work allocates and burns some CPU so every profile has samples
+func work() int {
+	sum := 0
+	for i := range 200000 {
+		s := make([]int, i%64+1)
+		sum += len(s)
+	}
+	return sum
+}
+
+// TestSession checks that each selected profile is written and named after the session
+func TestSession(t *testing.T) {
+	dir := t.TempDir()
+	s, err := Start(Options{CPU: true, Mem: true, Block: true, Trace: true, Dir: dir}, "day6-part2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	work()
+	files, err := s.Stop()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, path := range files {
+		names = append(names, filepath.Base(path))
+		if info, err := os.Stat(path); err != nil || info.Size() == 0 {
+			t.Errorf("%s is empty or missing: %v", path, err)
+		}
+	}
+	want := []string{"day6-part2.cpu.pprof", "day6-part2.mem.pprof", "day6-part2.mem.base.pprof", "day6-part2.block.pprof", "day6-part2.trace"}
+	if !slices.Equal(names, want) {
+		t.Errorf("files = %v, want %v", names, want)
+	}
+
+	// A second session can start once the first stopped
+	s, err = Start(Options{CPU: true, Dir: dir}, "again")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if files, err := s.Stop(); err != nil || len(files) != 1 {
+		t.Errorf("second session wrote %v, %v", files, err)
+	}
+}
+
+// TestStartFails checks that a failed start leaves nothing recording
+func TestStartFails(t *testing.T) {
+	if _, err := Start(Options{CPU: true, Dir: filepath.Join(t.TempDir(), "missing")}, "day1-part1"); err == nil {
+		t.Fatal("Start wrote into a missing directory")
+	}
+	s, err := Start(Options{CPU: true, Dir: t.TempDir()}, "day1-part1")
+	if err != nil {
+		t.Fatalf("CPU profile still running after a failed start: %v", err)
+	}
+	s.Stop()
+}
+
+// TestTop checks the hotspot summary, it needs the go command
+func TestTop(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds go tool pprof")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("no go command")
+	}
+	s, err := Start(Options{Mem: true, Dir: t.TempDir()}, "day1-part1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	work()
+	files, err := s.Stop()
+	if err != nil {
+		t.Fatal(err)
+	}
+	summary, err := Top(t.Context(), files[0], 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(summary, "flat%") {
+		t.Errorf("summary has no table:\n%s", summary)
+	}
+}
@@ -0,0 +1,135 @@
+// Package registry maps Advent of Code puzzles to the solvers that answer them.
+// Each day registers itself from an init function so the CLI can run any
+// puzzle without editing code.
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+)
+
+// Solver is implemented by every day.
+// Parse reads the puzzle input once and the parsed state is shared by both parts.
+// Part1 and Part2 may be called in either order and any number of times.
+// Long running parts check ctx and return its error once it is cancelled.
+type Solver interface {
+	Parse(r io.Reader) error
+	Part1(ctx context.Context) (int64, error)
+	Part2(ctx context.Context) (int64, error)
+}
+
+// ErrNotImplemented is returned by a part that has not been solved yet
+var ErrNotImplemented = errors.New("not implemented")
+
+// Puzzle is a single registered day of a year
+type Puzzle struct {
+	Year int
+	Day  int
+	New  func() Solver // Returns a fresh solver with no parsed input
+}
+
+// LogSetter is implemented by solvers that log diagnostics, usually by embedding Logs
+type LogSetter interface {
+	SetLogger(log *slog.Logger)
+}
+
+// Logs is embedded in a solver to receive the logger of its day.
+// Solvers log diagnostics through it instead of printing, so stdout only carries answers.
+type Logs struct {
+	log *slog.Logger
+}
+
+// discard is the logger of a solver that was never given one
+var discard = slog.New(slog.DiscardHandler)
+
+// SetLogger sets the logger returned by Log
+func (l *Logs) SetLogger(log *slog.Logger) {
+	l.log = log
+}
+
+// Log returns the solver's logger, one that discards everything when none was set
+func (l *Logs) Log() *slog.Logger {
+	if l.log == nil {
+		return discard
+	}
+	return l.log
+}
+
+// ExampleSetter is implemented by solvers whose examples differ from the real
+// input in more than their size, like the smaller room of the Day 14 example.
+// SetExample is called before Parse and Lint.
+type ExampleSetter interface {
+	SetExample(example bool)
+}
+
+// SetExample tells a solver or a linter whether its input is an example from
+// the puzzle text, for those that implement ExampleSetter
+func SetExample(s any, example bool) {
+	if setter, ok := s.(ExampleSetter); ok {
+		setter.SetExample(example)
+	}
+}
+
+// NewSolver returns a fresh solver for a puzzle that logs to log with the day attached.
+// A nil log leaves the solver silent.
+func NewSolver(p Puzzle, log *slog.Logger) Solver {
+	s := p.New()
+	if setter, ok := s.(LogSetter); ok && log != nil {
+		setter.SetLogger(log.With("day", p.Day))
+	}
+	return s
+}
+
+// Solve runs the given part, 1 or 2, of a solver that has already parsed its input
+func Solve(ctx context.Context, s Solver, part int) (int64, error) {
+	switch part {
+	case 1:
+		return s.Part1(ctx)
+	case 2:
+		return s.Part2(ctx)
+	}
+	return 0, fmt.Errorf("invalid part %d, want 1 or 2", part)
+}
+
+// key identifies a puzzle by year and day
+type key struct {
+	year, day int
+}
+
+// puzzles holds every registered puzzle
+var puzzles = make(map[key]Puzzle)
+
+// Register adds a puzzle to the registry.
+// It panics if the same year and day is registered twice.
+func Register(p Puzzle) {
+	k := key{year: p.Year, day: p.Day}
+	if _, exists := puzzles[k]; exists {
+		panic(fmt.Sprintf("registry: %d day %d registered twice", p.Year, p.Day))
+	}
+	puzzles[k] = p
+}
+
+// Lookup returns the puzzle registered for the given year and day
+func Lookup(year, day int) (Puzzle, bool) {
+	p, ok := puzzles[key{year: year, day: day}]
+	return p, ok
+}
+
+// All returns every registered puzzle ordered by year and day
+func All() []Puzzle {
+	all := make([]Puzzle, 0, len(puzzles))
+	for _, p := range puzzles {
+		all = append(all, p)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].Year != all[j].Year {
+			return all[i].Year < all[j].Year
+		}
+		return all[i].Day < all[j].Day
+	})
+	return all
+}
@@ -0,0 +1,189 @@
+// Package render draws grids as raster images and writes them as PNG or
+// animated GIF with the standard library encoders. Each cell becomes a square
+// of pixels coloured by a palette function.
+package render
+
+import (
+	"adventcode2024/utils/grid"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"io"
+	"math"
+	"strings"
+)
+
+// Colours shared by the days so pictures read the same everywhere
+var (
+	Background = color.RGBA{0xf4, 0xf1, 0xea, 0xff} // Empty cells
+	Wall       = color.RGBA{0x33, 0x33, 0x3b, 0xff} // Obstacles
+	Path       = color.RGBA{0x4f, 0x8f, 0xd8, 0xff} // Visited cells
+	Highlight  = color.RGBA{0xf5, 0xb8, 0x2e, 0xff} // Cells the puzzle asks about
+	Marker     = color.RGBA{0xd9, 0x3b, 0x3b, 0xff} // A single point of interest, like the guard
+)
+
+// DefaultScale is the side of a cell in pixels
+const DefaultScale = 8
+
+// Drawable is implemented by solvers that can draw the interesting state of their puzzle.
+// Draw may solve a part first to have a state worth drawing.
+type Drawable interface {
+	Draw(ctx context.Context, scale int) (image.Image, error)
+}
+
+// Palette returns the colour of a cell
+type Palette[T any] func(p grid.Point, v T) color.Color
+
+// Image draws g with every cell a scale by scale square of its palette colour
+func Image[T any](g *grid.Grid[T], palette Palette[T], scale int) *image.RGBA {
+	scale = max(scale, 1)
+	img := image.NewRGBA(image.Rect(0, 0, g.Cols()*scale, g.Rows()*scale))
+	for p, v := range g.All() {
+		cell := image.Rect(p.Col*scale, p.Row*scale, (p.Col+1)*scale, (p.Row+1)*scale)
+		draw.Draw(img, cell, image.NewUniform(palette(p, v)), image.Point{}, draw.Src)
+	}
+	return img
+}
+
+// Distinct returns the i-th of a sequence of colours where neighbours differ clearly.
+// Hues step by the golden angle so any number of regions or frequencies can be told apart.
+func Distinct(i int) color.Color {
+	hue := math.Mod(float64(i)*137.508, 360)
+	return hsv(hue, 0.55, 0.9)
+}
+
+// hsv converts a hue in degrees and saturation and value in [0,1] to a colour
+func hsv(h, s, v float64) color.RGBA {
+	c := v * s
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := v - c
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = c, x, 0
+	case h < 120:
+		r, g, b = x, c, 0
+	case h < 180:
+		r, g, b = 0, c, x
+	case h < 240:
+		r, g, b = 0, x, c
+	case h < 300:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+	return color.RGBA{uint8((r + m) * 255), uint8((g + m) * 255), uint8((b + m) * 255), 0xff}
+}
+
+// TextColor is the palette of text frames, see Text
+func TextColor(_ grid.Point, r rune) color.Color {
+	switch {
+	case r == '.' || r == ' ':
+		return Background
+	case r == '#':
+		return Wall
+	case r == 'x' || r == 'X':
+		return Path
+	case r == '^' || r == '>' || r == 'v' || r == '<' || r == '@':
+		return Marker
+	case r >= '0' && r <= '9':
+		return Distinct(int(r - '0'))
+	}
+	return Distinct(int(r))
+}
+
+// Text reads a text frame, such as an anim.Frame, into a grid of runes.
+// Frames that put a space after every cell are read one cell per two columns,
+// short lines are padded with spaces.
+func Text(text string) *grid.Grid[rune] {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	spaced := true
+	cols := 0
+	for _, line := range lines {
+		runes := []rune(line)
+		for i := 1; i < len(runes); i += 2 {
+			spaced = spaced && runes[i] == ' '
+		}
+		cols = max(cols, len(runes))
+	}
+	if spaced {
+		cols = (cols + 1) / 2
+	}
+
+	g := grid.New[rune](len(lines), cols)
+	g.Fill(' ')
+	for row, line := range lines {
+		runes := []rune(line)
+		for col := 0; col < cols; col++ {
+			i := col
+			if spaced {
+				i = 2 * col
+			}
+			if i < len(runes) {
+				g.Set(grid.Point{Row: row, Col: col}, runes[i])
+			}
+		}
+	}
+	return g
+}
+
+// WritePNG encodes img as a PNG
+func WritePNG(w io.Writer, img image.Image) error {
+	return png.Encode(w, img)
+}
+
+// ErrNoFrames is returned when a GIF is asked for without frames
+var ErrNoFrames = errors.New("no frames to encode")
+
+// WriteGIF encodes frames as an animated GIF showing each frame for delay
+// hundredths of a second and holding the last one. Frames of different sizes
+// are drawn on a canvas the size of the largest one.
+func WriteGIF(w io.Writer, frames []image.Image, delay int) error {
+	if len(frames) == 0 {
+		return ErrNoFrames
+	}
+	var bounds image.Rectangle
+	for _, f := range frames {
+		bounds = bounds.Union(f.Bounds())
+	}
+	palette := paletteOf(frames)
+
+	anim := &gif.GIF{LoopCount: 0}
+	for i, f := range frames {
+		paletted := image.NewPaletted(bounds, palette)
+		draw.Draw(paletted, bounds, image.NewUniform(Background), image.Point{}, draw.Src)
+		draw.Draw(paletted, f.Bounds(), f, f.Bounds().Min, draw.Src)
+		anim.Image = append(anim.Image, paletted)
+		d := delay
+		if i == len(frames)-1 {
+			d = max(delay, 200) // Hold the final state before looping
+		}
+		anim.Delay = append(anim.Delay, d)
+	}
+	return gif.EncodeAll(w, anim)
+}
+
+// paletteOf collects the colours used by the frames. Frames drawn from grids
+// use few colours, past the 256 a GIF allows the rest map to the nearest one.
+func paletteOf(frames []image.Image) color.Palette {
+	seen := map[color.RGBA]bool{}
+	palette := color.Palette{Background}
+	seen[Background] = true
+	for _, f := range frames {
+		b := f.Bounds()
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				c := color.RGBAModel.Convert(f.At(x, y)).(color.RGBA)
+				if !seen[c] && len(palette) < 256 {
+					seen[c] = true
+					palette = append(palette, c)
+				}
+			}
+		}
+	}
+	return palette
+}
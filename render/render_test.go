@@ -0,0 +1,92 @@
+package render
+
+import (
+	"adventcode2024/utils/grid"
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"testing"
+)
+
+func TestImage(t *testing.T) {
+	g := Text("#.\n.#\n..")
+	img := Image(g, TextColor, 3)
+	if b := img.Bounds(); b.Dx() != 6 || b.Dy() != 9 {
+		t.Fatalf("bounds %v, want 6x9", b)
+	}
+	for _, tt := range []struct {
+		x, y int
+		want color.RGBA
+	}{
+		{0, 0, Wall}, {2, 2, Wall}, {3, 0, Background}, {5, 5, Wall}, {1, 8, Background},
+	} {
+		if got := img.RGBAAt(tt.x, tt.y); got != tt.want {
+			t.Errorf("pixel %d,%d = %v, want %v", tt.x, tt.y, got, tt.want)
+		}
+	}
+}
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		text       string
+		rows, cols int
+		at         grid.Point
+		want       rune
+	}{
+		{text: "#.#\n...\n", rows: 2, cols: 3, at: grid.Point{Row: 0, Col: 2}, want: '#'},
+		{text: "# . #\n. . .", rows: 2, cols: 3, at: grid.Point{Row: 0, Col: 2}, want: '#'},
+		{text: "##\n#", rows: 2, cols: 2, at: grid.Point{Row: 1, Col: 1}, want: ' '},
+	}
+	for _, tt := range tests {
+		g := Text(tt.text)
+		if g.Rows() != tt.rows || g.Cols() != tt.cols {
+			t.Errorf("Text(%q) is %dx%d, want %dx%d", tt.text, g.Rows(), g.Cols(), tt.rows, tt.cols)
+			continue
+		}
+		if got := g.At(tt.at); got != tt.want {
+			t.Errorf("Text(%q) at %v = %q, want %q", tt.text, tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestDistinct(t *testing.T) {
+	for i := range 50 {
+		if Distinct(i) == Distinct(i+1) {
+			t.Errorf("Distinct(%d) and Distinct(%d) are both %v", i, i+1, Distinct(i))
+		}
+	}
+}
+
+func TestWriteGIF(t *testing.T) {
+	small := Image(Text("#."), TextColor, 2)
+	large := Image(Text("..\n.#"), TextColor, 2)
+
+	var buf bytes.Buffer
+	if err := WriteGIF(&buf, []image.Image{small, large, small}, 5); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Image) != 3 {
+		t.Fatalf("%d frames, want 3", len(decoded.Image))
+	}
+	if want := []int{5, 5, 200}; decoded.Delay[0] != want[0] || decoded.Delay[2] != want[2] {
+		t.Errorf("delays %v, want %v", decoded.Delay, want)
+	}
+	for i, frame := range decoded.Image {
+		if b := frame.Bounds(); b.Dx() != 4 || b.Dy() != 4 {
+			t.Errorf("frame %d bounds %v, want 4x4", i, b)
+		}
+	}
+	if got := color.RGBAModel.Convert(decoded.Image[0].At(0, 0)); got != Wall {
+		t.Errorf("first frame starts with %v, want %v", got, Wall)
+	}
+
+	if err := WriteGIF(&buf, nil, 5); !errors.Is(err, ErrNoFrames) {
+		t.Errorf("WriteGIF without frames = %v, want %v", err, ErrNoFrames)
+	}
+}
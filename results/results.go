@@ -0,0 +1,188 @@
+// Package results models the outcome of running a puzzle part and renders
+// results as a human readable table, JSON lines or CSV.
+package results
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+// Formats lists the names accepted by NewWriter
+var Formats = []string{"table", "json", "csv"}
+
+// Result is the outcome of running one part of a day on one input
+type Result struct {
+	Year     int
+	Day      int
+	Part     int
+	Input    string // Input variant, "input", "test", a path, ...
+	Answer   int64  // Only meaningful when Err is nil
+	Duration time.Duration
+	Err      error
+}
+
+// record is the JSON form of a Result
+type record struct {
+	Year       int    `json:"year"`
+	Day        int    `json:"day"`
+	Part       int    `json:"part"`
+	Input      string `json:"input"`
+	Answer     *int64 `json:"answer"`
+	DurationNs int64  `json:"duration_ns"`
+	Error      string `json:"error,omitempty"`
+}
+
+// MarshalJSON encodes a result with a null answer when it failed
+func (r Result) MarshalJSON() ([]byte, error) {
+	rec := record{Year: r.Year, Day: r.Day, Part: r.Part, Input: r.Input, DurationNs: r.Duration.Nanoseconds()}
+	if r.Err != nil {
+		rec.Error = r.Err.Error()
+	} else {
+		answer := r.Answer
+		rec.Answer = &answer
+	}
+	return json.Marshal(rec)
+}
+
+// UnmarshalJSON decodes a result written by MarshalJSON.
+// A failed result gets an error with the same message.
+func (r *Result) UnmarshalJSON(data []byte) error {
+	var rec record
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return err
+	}
+	*r = Result{Year: rec.Year, Day: rec.Day, Part: rec.Part, Input: rec.Input, Duration: time.Duration(rec.DurationNs)}
+	switch {
+	case rec.Error != "":
+		r.Err = errors.New(rec.Error)
+	case rec.Answer != nil:
+		r.Answer = *rec.Answer
+	}
+	return nil
+}
+
+// ReadJSON reads the results written by the json format, one object per line
+func ReadJSON(rd io.Reader) ([]Result, error) {
+	var all []Result
+	dec := json.NewDecoder(rd)
+	for {
+		var r Result
+		err := dec.Decode(&r)
+		if err == io.EOF {
+			return all, nil
+		}
+		if err != nil {
+			return all, err
+		}
+		all = append(all, r)
+	}
+}
+
+// answerText returns the answer, or an empty string when the part failed
+func (r Result) answerText() string {
+	if r.Err != nil {
+		return ""
+	}
+	return strconv.FormatInt(r.Answer, 10)
+}
+
+// errorText returns the error message, or an empty string when the part succeeded
+func (r Result) errorText() string {
+	if r.Err == nil {
+		return ""
+	}
+	return r.Err.Error()
+}
+
+// Writer renders results one at a time.
+// Flush must be called after the last result.
+type Writer interface {
+	Write(r Result) error
+	Flush() error
+}
+
+// NewWriter returns a Writer for the named format: "table", "json" or "csv"
+func NewWriter(w io.Writer, format string) (Writer, error) {
+	switch format {
+	case "table":
+		return &tableWriter{w: tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)}, nil
+	case "json":
+		return &jsonWriter{enc: json.NewEncoder(w)}, nil
+	case "csv":
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	}
+	return nil, fmt.Errorf("unknown format %q, want one of %v", format, Formats)
+}
+
+// tableWriter aligns results in columns for people to read
+type tableWriter struct {
+	w           *tabwriter.Writer
+	wroteHeader bool
+}
+
+func (t *tableWriter) Write(r Result) error {
+	if !t.wroteHeader {
+		t.wroteHeader = true
+		if _, err := fmt.Fprintln(t.w, "YEAR\tDAY\tPART\tINPUT\tANSWER\tTIME"); err != nil {
+			return err
+		}
+	}
+	answer := r.answerText()
+	if r.Err != nil {
+		answer = "error: " + r.Err.Error()
+	}
+	_, err := fmt.Fprintf(t.w, "%d\t%d\t%d\t%s\t%s\t%s\n", r.Year, r.Day, r.Part, r.Input, answer, r.Duration.Round(time.Microsecond))
+	return err
+}
+
+func (t *tableWriter) Flush() error {
+	return t.w.Flush()
+}
+
+// jsonWriter writes one JSON object per line
+type jsonWriter struct {
+	enc *json.Encoder
+}
+
+func (j *jsonWriter) Write(r Result) error {
+	return j.enc.Encode(r)
+}
+
+func (j *jsonWriter) Flush() error {
+	return nil
+}
+
+// csvWriter writes a header row followed by one row per result
+type csvWriter struct {
+	w           *csv.Writer
+	wroteHeader bool
+}
+
+func (c *csvWriter) Write(r Result) error {
+	if !c.wroteHeader {
+		c.wroteHeader = true
+		if err := c.w.Write([]string{"year", "day", "part", "input", "answer", "duration_ns", "error"}); err != nil {
+			return err
+		}
+	}
+	return c.w.Write([]string{
+		strconv.Itoa(r.Year),
+		strconv.Itoa(r.Day),
+		strconv.Itoa(r.Part),
+		r.Input,
+		r.answerText(),
+		strconv.FormatInt(r.Duration.Nanoseconds(), 10),
+		r.errorText(),
+	})
+}
+
+func (c *csvWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
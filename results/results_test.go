@@ -0,0 +1,91 @@
+package results
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sample is one successful and one failed result
+var sample = []Result{
+	{Year: 2024, Day: 7, Part: 1, Input: "test", Answer: 3749, Duration: 1500 * time.Microsecond},
+	{Year: 2024, Day: 8, Part: 1, Input: "test", Err: errors.New("not implemented")},
+}
+
+// render writes the sample in the given format
+func render(t *testing.T, format string) string {
+	t.Helper()
+	var sb strings.Builder
+	w, err := NewWriter(&sb, format)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range sample {
+		if err := w.Write(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	return sb.String()
+}
+
+// TestFormats checks the exact output of every format
+func TestFormats(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"json", `{"year":2024,"day":7,"part":1,"input":"test","answer":3749,"duration_ns":1500000}
+{"year":2024,"day":8,"part":1,"input":"test","answer":null,"duration_ns":0,"error":"not implemented"}
+`},
+		{"csv", `year,day,part,input,answer,duration_ns,error
+2024,7,1,test,3749,1500000,
+2024,8,1,test,,0,not implemented
+`},
+		{"table", `YEAR  DAY  PART  INPUT  ANSWER                  TIME
+2024  7    1     test   3749                    1.5ms
+2024  8    1     test   error: not implemented  0s
+`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			if got := render(t, tt.format); got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestReadJSON checks that results written as JSON read back the same
+func TestReadJSON(t *testing.T) {
+	got, err := ReadJSON(strings.NewReader(render(t, "json")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(sample) {
+		t.Fatalf("read %d results, want %d", len(got), len(sample))
+	}
+	for i, want := range sample {
+		g := got[i]
+		if g.Err != nil && want.Err != nil && g.Err.Error() == want.Err.Error() {
+			g.Err = want.Err
+		}
+		if g != want {
+			t.Errorf("result %d = %+v, want %+v", i, g, want)
+		}
+	}
+
+	if _, err := ReadJSON(strings.NewReader(`{"year":2024,"day":`)); err == nil {
+		t.Error("ReadJSON accepted a cut off line")
+	}
+}
+
+// TestUnknownFormat checks that NewWriter rejects unknown formats
+func TestUnknownFormat(t *testing.T) {
+	if _, err := NewWriter(&strings.Builder{}, "xml"); err == nil {
+		t.Error("NewWriter accepted xml")
+	}
+}
@@ -0,0 +1,166 @@
+// Package runner solves puzzles with a pool of workers, each puzzle under its
+// own timeout, and collects the results in a deterministic order.
+package runner
+
+import (
+	"adventcode2024/input"
+	"adventcode2024/registry"
+	"adventcode2024/results"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Job is one puzzle to solve on one input
+type Job struct {
+	Puzzle  registry.Puzzle
+	Parts   []int // Parts to solve, in the order their results are returned
+	Variant input.Variant
+}
+
+// Runner solves jobs concurrently
+type Runner struct {
+	Loader  *input.Loader
+	Workers int           // Jobs solved at the same time, at least 1
+	Timeout time.Duration // Limit for parsing and solving one job, 0 for no limit
+	Log     *slog.Logger  // Diagnostics of the solvers, nil keeps them silent
+}
+
+// Run solves every job and returns the results in job order, then part order.
+// A job that fails, panics or times out only fails its own results.
+// Cancelling ctx fails the results of every job that has not finished.
+func (r *Runner) Run(ctx context.Context, jobs []Job) []results.Result {
+	perJob := make([][]results.Result, len(jobs))
+	next := make(chan int)
+
+	var wg sync.WaitGroup
+	for range max(r.Workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				perJob[i] = r.solve(ctx, jobs[i])
+			}
+		}()
+	}
+	for i := range jobs {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	var all []results.Result
+	for _, res := range perJob {
+		all = append(all, res...)
+	}
+	return all
+}
+
+// solved is the result of one part, sent back by the goroutine solving a job
+type solved struct {
+	index  int
+	result results.Result
+}
+
+// solve runs one job under the timeout.
+// The job runs in its own goroutine so that a solver which does not check its
+// context cannot hold up the worker, it is abandoned once the context is done.
+func (r *Runner) solve(ctx context.Context, job Job) []results.Result {
+	if r.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.Timeout)
+		defer cancel()
+	}
+
+	res := make([]results.Result, len(job.Parts))
+	for i, part := range job.Parts {
+		res[i] = results.Result{Year: job.Puzzle.Year, Day: job.Puzzle.Day, Part: part, Input: job.Variant.String()}
+	}
+
+	// Buffered so an abandoned goroutine never blocks
+	parts := make(chan solved, len(res))
+	go r.solveParts(ctx, job, res, parts)
+
+	finished := make([]bool, len(res))
+	for {
+		select {
+		case s, ok := <-parts:
+			if !ok {
+				return res
+			}
+			res[s.index] = s.result
+			finished[s.index] = true
+		case <-ctx.Done():
+			err := ctx.Err()
+			if errors.Is(err, context.DeadlineExceeded) {
+				err = fmt.Errorf("timed out after %v: %w", r.Timeout, err)
+			}
+			for i := range res {
+				if !finished[i] {
+					res[i].Err = err
+				}
+			}
+			return res
+		}
+	}
+}
+
+// solveParts parses the input once, solves each part and sends every result to parts.
+// res holds the labelled results to fill in, it is copied before being changed.
+func (r *Runner) solveParts(ctx context.Context, job Job, res []results.Result, parts chan<- solved) {
+	res = append([]results.Result(nil), res...)
+	defer close(parts)
+
+	solver := registry.NewSolver(job.Puzzle, r.Log)
+	if err := r.parse(solver, job); err != nil {
+		for i := range res {
+			res[i].Err = err
+			parts <- solved{i, res[i]}
+		}
+		return
+	}
+
+	for i, part := range job.Parts {
+		start := time.Now()
+		res[i].Answer, res[i].Err = SolveSafely(ctx, solver, part)
+		res[i].Duration = time.Since(start)
+		parts <- solved{i, res[i]}
+	}
+}
+
+// parse opens and parses the input of a job, turning a panicking parser into an error
+func (r *Runner) parse(solver registry.Solver, job Job) (err error) {
+	defer recoverError(&err, "parse")
+
+	file, err := r.Loader.Open(job.Puzzle.Day, job.Variant)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	registry.SetExample(solver, job.Variant.Kind == input.Example)
+	if err := solver.Parse(file); err != nil {
+		name := r.Loader.Path(job.Puzzle.Day, job.Variant)
+		if job.Variant.Kind == input.Stdin {
+			name = "stdin"
+		}
+		return fmt.Errorf("parse: %w", input.WithFile(err, name))
+	}
+	return nil
+}
+
+// SolveSafely runs one part, turning a panicking solver into an error
+func SolveSafely(ctx context.Context, solver registry.Solver, part int) (answer int64, err error) {
+	defer recoverError(&err, fmt.Sprintf("part %d", part))
+	return registry.Solve(ctx, solver, part)
+}
+
+// recoverError turns a panic of what into an error stored in err, it must be deferred
+func recoverError(err *error, what string) {
+	if p := recover(); p != nil {
+		*err = fmt.Errorf("%s panicked: %v", what, p)
+	}
+}
@@ -0,0 +1,98 @@
+package runner
+
+import (
+	"adventcode2024/input"
+	"adventcode2024/registry"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fakeSolver answers its day number for part 1 and behaves as told for part 2
+type fakeSolver struct {
+	day   int
+	part2 func(ctx context.Context) (int64, error)
+}
+
+func (f *fakeSolver) Parse(r io.Reader) error {
+	_, err := io.ReadAll(r)
+	return err
+}
+
+func (f *fakeSolver) Part1(ctx context.Context) (int64, error) { return int64(f.day), nil }
+
+func (f *fakeSolver) Part2(ctx context.Context) (int64, error) { return f.part2(ctx) }
+
+// job returns a job for a fake day whose part 2 runs part2
+func job(day int, variant input.Variant, part2 func(ctx context.Context) (int64, error)) Job {
+	p := registry.Puzzle{Year: 2024, Day: day, New: func() registry.Solver { return &fakeSolver{day: day, part2: part2} }}
+	return Job{Puzzle: p, Parts: []int{1, 2}, Variant: variant}
+}
+
+// TestRun checks result order, timeouts of solvers that ignore their context, and panics
+func TestRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "input.txt")
+	if err := os.WriteFile(path, []byte("1 2 3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	variant := input.Variant{Kind: input.Path, Path: path}
+
+	hang := make(chan struct{})
+	defer close(hang)
+	jobs := []Job{
+		job(1, variant, func(ctx context.Context) (int64, error) {
+			time.Sleep(20 * time.Millisecond) // Finishes after the jobs behind it
+			return 10, nil
+		}),
+		job(2, variant, func(ctx context.Context) (int64, error) {
+			<-hang // Ignores ctx
+			return 20, nil
+		}),
+		job(3, variant, func(ctx context.Context) (int64, error) {
+			<-ctx.Done()
+			return 0, ctx.Err()
+		}),
+		job(4, variant, func(ctx context.Context) (int64, error) { panic("boom") }),
+		job(5, input.Variant{Kind: input.Path, Path: filepath.Join(t.TempDir(), "missing.txt")}, nil),
+	}
+
+	r := &Runner{Loader: &input.Loader{}, Workers: 3, Timeout: 100 * time.Millisecond}
+	res := r.Run(t.Context(), jobs)
+	if len(res) != 2*len(jobs) {
+		t.Fatalf("got %d results, want %d", len(res), 2*len(jobs))
+	}
+
+	for i, result := range res {
+		day, part := i/2+1, i%2+1
+		if result.Day != day || result.Part != part {
+			t.Errorf("result %d is day %d part %d, want day %d part %d", i, result.Day, result.Part, day, part)
+			continue
+		}
+		switch {
+		case day == 5:
+			if result.Err == nil {
+				t.Errorf("day 5 part %d read a missing input", part)
+			}
+		case part == 1:
+			if result.Err != nil || result.Answer != int64(day) {
+				t.Errorf("day %d part 1 = %d, %v, want %d", day, result.Answer, result.Err, day)
+			}
+		case day == 1:
+			if result.Err != nil || result.Answer != 10 {
+				t.Errorf("day 1 part 2 = %d, %v, want 10", result.Answer, result.Err)
+			}
+		case day == 2 || day == 3:
+			if !errors.Is(result.Err, context.DeadlineExceeded) {
+				t.Errorf("day %d part 2 error = %v, want a timeout", day, result.Err)
+			}
+		case day == 4:
+			if result.Err == nil {
+				t.Error("day 4 part 2 panicked without an error")
+			}
+		}
+	}
+}
@@ -0,0 +1,167 @@
+// Package scaffold generates the files of a new day from templates.
+// The default templates are embedded from the templates directory,
+// a directory with the same file names can replace them.
+package scaffold
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// Templates holds the default templates
+//
+//go:embed templates/*.tmpl
+var Templates embed.FS
+
+// Template file names, looked up at the root of the template file system
+const (
+	SolverTemplate = "solver.go.tmpl"      // Day/DayN.go
+	TestTemplate   = "solver_test.go.tmpl" // Entries of the example table in Day/solver_test.go
+)
+
+// GoldenFile holds the table of examples of every day, goldenCases, and
+// GoldenMarker is the line of the table the entries of a new day go above
+const (
+	GoldenFile   = "solver_test.go"
+	GoldenMarker = "// advent new adds the examples of a new day above this line"
+)
+
+// ErrExists is returned when a file of the new day is already there
+var ErrExists = errors.New("already exists")
+
+// Data is what the templates are executed with
+type Data struct {
+	Year   int
+	Day    int
+	Solver string // Name of the solver type, day15Solver
+}
+
+// NewData returns the template data for a day
+func NewData(year, day int) Data {
+	return Data{Year: year, Day: day, Solver: fmt.Sprintf("day%dSolver", day)}
+}
+
+// Default returns the embedded templates
+func Default() fs.FS {
+	templates, err := fs.Sub(Templates, "templates")
+	if err != nil {
+		panic(err)
+	}
+	return templates
+}
+
+// Generate writes the solver file of a day into dayDir, adds the entries of
+// the test template to the example table of GoldenFile in dayDir and writes
+// empty real and example inputs into inputsDir. Inputs already on disk,
+// fetched or written by hand, are kept. Nothing is written when a Go file of
+// the day exists already. It returns the files written.
+func Generate(templates fs.FS, data Data, dayDir, inputsDir string) ([]string, error) {
+	files := []struct {
+		path     string
+		template string // Empty for an empty file
+	}{
+		{filepath.Join(dayDir, fmt.Sprintf("Day%d.go", data.Day)), SolverTemplate},
+		{filepath.Join(dayDir, GoldenFile), TestTemplate},
+		{filepath.Join(inputsDir, fmt.Sprintf("input%d.txt", data.Day)), ""},
+		{filepath.Join(inputsDir, fmt.Sprintf("test%d.txt", data.Day)), ""},
+	}
+
+	// Render everything before writing so a bad template leaves no partial day behind
+	var existing []string
+	contents := make([][]byte, len(files))
+	for i, f := range files {
+		if f.template == "" {
+			continue
+		}
+		var content []byte
+		var err error
+		if f.template == TestTemplate {
+			content, err = addGolden(templates, data, f.path)
+		} else {
+			if _, err := os.Stat(f.path); err == nil {
+				existing = append(existing, f.path)
+			}
+			content, err = render(templates, f.template, data)
+		}
+		if err != nil {
+			return nil, err
+		}
+		contents[i] = content
+	}
+	if len(existing) > 0 {
+		return nil, fmt.Errorf("%s %w", strings.Join(existing, ", "), ErrExists)
+	}
+
+	written := make([]string, 0, len(files))
+	for i, f := range files {
+		if _, err := os.Stat(f.path); err == nil && f.template == "" {
+			continue
+		}
+		if err := os.WriteFile(f.path, contents[i], 0o644); err != nil {
+			return written, err
+		}
+		written = append(written, f.path)
+	}
+	return written, nil
+}
+
+// addGolden returns the test file at path with the entries of the test
+// template inserted above GoldenMarker
+func addGolden(templates fs.FS, data Data, path string) ([]byte, error) {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	before, after, ok := bytes.Cut(source, []byte(GoldenMarker))
+	if !ok {
+		return nil, fmt.Errorf("%s has no line %q to add the examples above", path, GoldenMarker)
+	}
+	entries, err := execute(templates, TestTemplate, data)
+	if err != nil {
+		return nil, err
+	}
+	// Keep the indentation of the marker on the line after the entries
+	indent := before[bytes.LastIndexByte(before, '\n')+1:]
+	before = before[:len(before)-len(indent)]
+	added := slices.Concat(before, entries, indent, []byte(GoldenMarker), after)
+	formatted, err := format.Source(added)
+	if err != nil {
+		return nil, fmt.Errorf("%s: generated code does not parse: %w", TestTemplate, err)
+	}
+	return formatted, nil
+}
+
+// execute executes one template
+func execute(templates fs.FS, name string, data Data) ([]byte, error) {
+	tmpl, err := template.ParseFS(templates, name)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// render executes one template and formats the result as Go source
+func render(templates fs.FS, name string, data Data) ([]byte, error) {
+	code, err := execute(templates, name, data)
+	if err != nil {
+		return nil, err
+	}
+	source, err := format.Source(code)
+	if err != nil {
+		return nil, fmt.Errorf("%s: generated code does not parse: %w", name, err)
+	}
+	return source, nil
+}
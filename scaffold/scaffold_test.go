@@ -0,0 +1,106 @@
+package scaffold
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// goldenTable is a table of examples as in Day/solver_test.go
+const goldenTable = `package Day
+
+var goldenCases = []struct {
+	day   int
+	input string
+	want  map[int]int64
+}{
+	{1, "test", map[int]int64{1: 11}},
+	` + GoldenMarker + `
+}
+`
+
+// newDayDir returns a directory holding the table of examples
+func newDayDir(t *testing.T) string {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, GoldenFile), []byte(goldenTable), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+// TestGenerate checks the files of a new day, the kept inputs and the refusal to overwrite
+func TestGenerate(t *testing.T) {
+	dayDir, inputsDir := newDayDir(t), t.TempDir()
+	fetched := filepath.Join(inputsDir, "input15.txt")
+	if err := os.WriteFile(fetched, []byte("#####\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	written, err := Generate(Default(), NewData(2024, 15), dayDir, inputsDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		filepath.Join(dayDir, "Day15.go"),
+		filepath.Join(dayDir, GoldenFile),
+		filepath.Join(inputsDir, "test15.txt"),
+	}
+	if strings.Join(written, " ") != strings.Join(want, " ") {
+		t.Errorf("written = %v, want %v", written, want)
+	}
+
+	solver, _ := os.ReadFile(filepath.Join(dayDir, "Day15.go"))
+	for _, s := range []string{"Year: 2024, Day: 15", "type day15Solver struct", "func (d *day15Solver) Part2"} {
+		if !strings.Contains(string(solver), s) {
+			t.Errorf("Day15.go does not contain %q", s)
+		}
+	}
+	table, _ := os.ReadFile(filepath.Join(dayDir, GoldenFile))
+	if !strings.Contains(string(table), "\t{15, \"test\", map[int]int64{}},\n\t"+GoldenMarker) {
+		t.Errorf("%s has no day 15 entry above the marker:\n%s", GoldenFile, table)
+	}
+	if data, _ := os.ReadFile(fetched); string(data) != "#####\n" {
+		t.Errorf("fetched input was overwritten with %q", data)
+	}
+
+	if _, err := Generate(Default(), NewData(2024, 15), dayDir, inputsDir); !errors.Is(err, ErrExists) {
+		t.Errorf("second Generate error = %v, want ErrExists", err)
+	}
+	if _, err := Generate(Default(), NewData(2024, 18), t.TempDir(), t.TempDir()); err == nil {
+		t.Error("Generate wrote a day without a table of examples to add it to")
+	}
+}
+
+// TestCustomTemplates checks that templates from another file system are used and validated
+func TestCustomTemplates(t *testing.T) {
+	custom := fstest.MapFS{
+		SolverTemplate: {Data: []byte("package Day\n\n// Day {{.Day}} of {{.Year}}\nvar {{.Solver}}Name = \"custom\"\n")},
+		TestTemplate:   {Data: []byte("{ {{- .Day}}, \"test2\", map[int]int64{1: 7}},\n")},
+	}
+	dayDir := newDayDir(t)
+	if _, err := Generate(custom, NewData(2024, 16), dayDir, t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	solver, _ := os.ReadFile(filepath.Join(dayDir, "Day16.go"))
+	if !strings.Contains(string(solver), `var day16SolverName = "custom"`) {
+		t.Errorf("Day16.go = %q", solver)
+	}
+	if table, _ := os.ReadFile(filepath.Join(dayDir, GoldenFile)); !strings.Contains(string(table), `{16, "test2", map[int]int64{1: 7}},`) {
+		t.Errorf("%s = %s", GoldenFile, table)
+	}
+
+	custom[SolverTemplate] = &fstest.MapFile{Data: []byte("package Day\nfunc {{.Day}}() {}\n")}
+	dayDir = newDayDir(t)
+	if _, err := Generate(custom, NewData(2024, 17), dayDir, t.TempDir()); err == nil {
+		t.Error("Generate accepted a template producing invalid Go")
+	}
+	if entries, _ := os.ReadDir(dayDir); len(entries) != 1 {
+		t.Errorf("invalid template left %d files behind", len(entries)-1)
+	}
+	if table, _ := os.ReadFile(filepath.Join(dayDir, GoldenFile)); string(table) != goldenTable {
+		t.Errorf("invalid template changed %s:\n%s", GoldenFile, table)
+	}
+}
@@ -0,0 +1,228 @@
+// Package grid provides a generic rectangular grid for the puzzles whose input is a map.
+// Positions are Points, row first, with row 0 at the top of the input.
+package grid
+
+import (
+	"adventcode2024/input"
+	"adventcode2024/utils/parse"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+)
+
+// Grid is a rectangle of cells of type T
+type Grid[T any] struct {
+	rows, cols int
+	cells      []T // Row after row
+}
+
+// New returns a grid of rows x cols zero cells
+func New[T any](rows, cols int) *Grid[T] {
+	return &Grid[T]{rows: rows, cols: cols, cells: make([]T, rows*cols)}
+}
+
+// Parse builds a grid from lines of text, mapping each rune to a cell.
+// The mapper reports false for runes it does not accept, expected describes
+// the accepted runes for the *input.ParseError returned in that case.
+// Ragged lines and an empty input are also reported as *input.ParseError.
+func Parse[T any](lines []string, mapper func(r rune) (T, bool), expected string) (*Grid[T], error) {
+	g := &Grid[T]{}
+	for row, line := range lines {
+		if err := g.appendRow(row+1, line, mapper, expected); err != nil {
+			return nil, err
+		}
+	}
+	if g.rows == 0 {
+		return nil, &input.ParseError{Line: 1, Expected: "a map"}
+	}
+	return g, nil
+}
+
+// Read reads the lines of r and parses them like Parse, keeping only the
+// cells in memory. Blank lines at the end of the input are ignored.
+func Read[T any](r io.Reader, mapper func(r rune) (T, bool), expected string) (*Grid[T], error) {
+	g := &Grid[T]{}
+	err := parse.Each(r, func(l parse.Line) error {
+		return g.appendRow(l.Number, l.Text, mapper, expected)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if g.rows == 0 {
+		return nil, &input.ParseError{Line: 1, Expected: "a map"}
+	}
+	return g, nil
+}
+
+// appendRow maps the runes of line, input line number n, to a new bottom row.
+// The first row sets the number of columns.
+func (g *Grid[T]) appendRow(n int, line string, mapper func(r rune) (T, bool), expected string) error {
+	if g.rows == 0 {
+		if line == "" {
+			return &input.ParseError{Line: 1, Expected: "a map"}
+		}
+		g.cols = len([]rune(line))
+	}
+	if len([]rune(line)) != g.cols {
+		return &input.ParseError{Line: n, Column: 1, Text: line,
+			Expected: fmt.Sprintf("a row of %d characters like the first row", g.cols)}
+	}
+	col := 0
+	for _, r := range line {
+		cell, ok := mapper(r)
+		if !ok {
+			return &input.ParseError{Line: n, Column: col + 1, Text: string(r), Expected: expected}
+		}
+		g.cells = append(g.cells, cell)
+		col++
+	}
+	g.rows++
+	return nil
+}
+
+// Runes is a Parse mapper that keeps the runes in chars as they are
+func Runes(chars string) func(r rune) (rune, bool) {
+	return func(r rune) (rune, bool) { return r, strings.ContainsRune(chars, r) }
+}
+
+// Rows returns the number of rows
+func (g *Grid[T]) Rows() int { return g.rows }
+
+// Cols returns the number of columns
+func (g *Grid[T]) Cols() int { return g.cols }
+
+// In reports whether p lies inside the grid
+func (g *Grid[T]) In(p Point) bool {
+	return p.Row >= 0 && p.Row < g.rows && p.Col >= 0 && p.Col < g.cols
+}
+
+// At returns the cell at p, p must lie inside the grid
+func (g *Grid[T]) At(p Point) T {
+	return *g.Ptr(p)
+}
+
+// Get returns the cell at p, or the zero value and false outside the grid
+func (g *Grid[T]) Get(p Point) (T, bool) {
+	if !g.In(p) {
+		var zero T
+		return zero, false
+	}
+	return g.cells[p.Row*g.cols+p.Col], true
+}
+
+// Ptr returns a pointer to the cell at p for updating it in place, p must lie inside the grid
+func (g *Grid[T]) Ptr(p Point) *T {
+	if !g.In(p) {
+		panic(fmt.Sprintf("grid: %v outside %dx%d grid", p, g.rows, g.cols))
+	}
+	return &g.cells[p.Row*g.cols+p.Col]
+}
+
+// Set stores v at p, p must lie inside the grid
+func (g *Grid[T]) Set(p Point, v T) {
+	*g.Ptr(p) = v
+}
+
+// Fill stores v in every cell
+func (g *Grid[T]) Fill(v T) {
+	for i := range g.cells {
+		g.cells[i] = v
+	}
+}
+
+// All iterates over every position and its cell, row by row
+func (g *Grid[T]) All() iter.Seq2[Point, T] {
+	return func(yield func(Point, T) bool) {
+		for i, cell := range g.cells {
+			if !yield(Point{i / g.cols, i % g.cols}, cell) {
+				return
+			}
+		}
+	}
+}
+
+// Neighbours iterates over the positions one step from p in each of dirs
+// that lie inside the grid, in the order of dirs
+func (g *Grid[T]) Neighbours(p Point, dirs []Point) iter.Seq[Point] {
+	return func(yield func(Point) bool) {
+		for _, dir := range dirs {
+			next := p.Add(dir)
+			if g.In(next) && !yield(next) {
+				return
+			}
+		}
+	}
+}
+
+// Neighbours4 iterates over the orthogonal neighbours of p inside the grid
+func (g *Grid[T]) Neighbours4(p Point) iter.Seq[Point] {
+	return g.Neighbours(p, Dirs4)
+}
+
+// Neighbours8 iterates over the orthogonal and diagonal neighbours of p inside the grid
+func (g *Grid[T]) Neighbours8(p Point) iter.Seq[Point] {
+	return g.Neighbours(p, Dirs8)
+}
+
+// FindAll returns the positions of the cells matching match, row by row
+func (g *Grid[T]) FindAll(match func(T) bool) []Point {
+	var points []Point
+	for p, cell := range g.All() {
+		if match(cell) {
+			points = append(points, p)
+		}
+	}
+	return points
+}
+
+// Clone returns a copy of the grid, cells are copied by assignment
+func (g *Grid[T]) Clone() *Grid[T] {
+	return &Grid[T]{rows: g.rows, cols: g.cols, cells: append([]T(nil), g.cells...)}
+}
+
+// remap returns a rows x cols grid where each cell is taken from g at from(p)
+func (g *Grid[T]) remap(rows, cols int, from func(p Point) Point) *Grid[T] {
+	out := New[T](rows, cols)
+	for i := range out.cells {
+		out.cells[i] = g.At(from(Point{i / cols, i % cols}))
+	}
+	return out
+}
+
+// Transpose returns the grid mirrored along its main diagonal
+func (g *Grid[T]) Transpose() *Grid[T] {
+	return g.remap(g.cols, g.rows, func(p Point) Point { return Point{p.Col, p.Row} })
+}
+
+// RotateRight returns the grid turned a quarter clockwise
+func (g *Grid[T]) RotateRight() *Grid[T] {
+	return g.remap(g.cols, g.rows, func(p Point) Point { return Point{g.rows - 1 - p.Col, p.Row} })
+}
+
+// RotateLeft returns the grid turned a quarter counter-clockwise
+func (g *Grid[T]) RotateLeft() *Grid[T] {
+	return g.remap(g.cols, g.rows, func(p Point) Point { return Point{p.Col, g.cols - 1 - p.Row} })
+}
+
+// FlipHorizontal returns the grid mirrored left to right
+func (g *Grid[T]) FlipHorizontal() *Grid[T] {
+	return g.remap(g.rows, g.cols, func(p Point) Point { return Point{p.Row, g.cols - 1 - p.Col} })
+}
+
+// FlipVertical returns the grid mirrored top to bottom
+func (g *Grid[T]) FlipVertical() *Grid[T] {
+	return g.remap(g.rows, g.cols, func(p Point) Point { return Point{g.rows - 1 - p.Row, p.Col} })
+}
+
+// Render draws the grid as text, one line per row, using cell to draw each cell
+func (g *Grid[T]) Render(cell func(p Point, v T) string) string {
+	var sb strings.Builder
+	for p, v := range g.All() {
+		sb.WriteString(cell(p, v))
+		if p.Col == g.cols-1 {
+			sb.WriteByte('\n')
+		}
+	}
+	return sb.String()
+}
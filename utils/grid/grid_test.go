@@ -0,0 +1,121 @@
+package grid
+
+import (
+	"adventcode2024/input"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// letters parses a small grid of letters
+func letters(t *testing.T, text string) *Grid[rune] {
+	t.Helper()
+	g, err := Parse(strings.Split(text, "\n"), Runes("ABCDEF"), "a letter A-F")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+// text renders a grid of runes
+func text(g *Grid[rune]) string {
+	return g.Render(func(_ Point, r rune) string { return string(r) })
+}
+
+// TestParseErrors checks that ragged rows and unknown runes are reported where they are,
+// by Parse and by Read
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		text         string
+		line, column int
+	}{
+		{"AB\nC", 2, 1},
+		{"AB\nCx", 2, 2},
+		{"", 1, 0},
+	}
+	for _, tt := range tests {
+		_, err := Parse(strings.Split(tt.text, "\n"), Runes("ABC"), "A, B or C")
+		var pe *input.ParseError
+		if !errors.As(err, &pe) || pe.Line != tt.line || pe.Column != tt.column {
+			t.Errorf("Parse(%q) error = %v, want a ParseError at %d:%d", tt.text, err, tt.line, tt.column)
+		}
+		_, err = Read(strings.NewReader(tt.text+"\n\n"), Runes("ABC"), "A, B or C")
+		if !errors.As(err, &pe) || pe.Line != tt.line || pe.Column != tt.column {
+			t.Errorf("Read(%q) error = %v, want a ParseError at %d:%d", tt.text, err, tt.line, tt.column)
+		}
+	}
+}
+
+// TestAccess checks bounds-checked access and neighbour iteration
+func TestAccess(t *testing.T) {
+	g := letters(t, "ABC\nDEF")
+	if g.Rows() != 2 || g.Cols() != 3 {
+		t.Fatalf("size %dx%d, want 2x3", g.Rows(), g.Cols())
+	}
+	if v, ok := g.Get(Point{1, 2}); !ok || v != 'F' {
+		t.Errorf("Get(1,2) = %c %v, want F true", v, ok)
+	}
+	if _, ok := g.Get(Point{2, 0}); ok {
+		t.Error("Get(2,0) is inside a 2x3 grid")
+	}
+
+	corner := slices.Collect(g.Neighbours4(Point{0, 0}))
+	if want := []Point{{0, 1}, {1, 0}}; !slices.Equal(corner, want) {
+		t.Errorf("Neighbours4(0,0) = %v, want %v", corner, want)
+	}
+	if n := len(slices.Collect(g.Neighbours8(Point{0, 1}))); n != 5 {
+		t.Errorf("Neighbours8(0,1) has %d points, want 5", n)
+	}
+
+	vowels := g.FindAll(func(r rune) bool { return r == 'A' || r == 'E' })
+	if want := []Point{{0, 0}, {1, 1}}; !slices.Equal(vowels, want) {
+		t.Errorf("FindAll = %v, want %v", vowels, want)
+	}
+}
+
+// TestTransforms checks transpose, rotations and flips against their drawings
+func TestTransforms(t *testing.T) {
+	g := letters(t, "ABC\nDEF")
+	tests := []struct {
+		name string
+		got  *Grid[rune]
+		want string
+	}{
+		{"transpose", g.Transpose(), "AD\nBE\nCF\n"},
+		{"rotate right", g.RotateRight(), "DA\nEB\nFC\n"},
+		{"rotate left", g.RotateLeft(), "CF\nBE\nAD\n"},
+		{"flip horizontal", g.FlipHorizontal(), "CBA\nFED\n"},
+		{"flip vertical", g.FlipVertical(), "DEF\nABC\n"},
+	}
+	for _, tt := range tests {
+		if got := text(tt.got); got != tt.want {
+			t.Errorf("%s:\n%s\nwant\n%s", tt.name, got, tt.want)
+		}
+	}
+
+	if got := text(g.RotateRight().RotateLeft()); got != text(g) {
+		t.Errorf("rotating right then left gives\n%s", got)
+	}
+}
+
+// FuzzRead checks that Read rejects any input that is not a map with a
+// *input.ParseError and otherwise keeps every cell
+func FuzzRead(f *testing.F) {
+	f.Add("..#\n.^.\n#..\n")
+	f.Add("AB\nC")
+	f.Add("\n\nA\n")
+	f.Fuzz(func(t *testing.T, text string) {
+		g, err := Read(strings.NewReader(text), Runes(".#^"), "'.', '#' or '^'")
+		var pe *input.ParseError
+		if err != nil {
+			if !errors.As(err, &pe) {
+				t.Errorf("Read(%q) returned %v, want a *input.ParseError", text, err)
+			}
+			return
+		}
+		if len(g.cells) != g.Rows()*g.Cols() || g.Cols() == 0 {
+			t.Errorf("Read(%q) gave %d cells for %dx%d", text, len(g.cells), g.Rows(), g.Cols())
+		}
+	})
+}
@@ -0,0 +1,56 @@
+package grid
+
+import "strconv"
+
+// Point is a position in a grid, or an offset between two positions
+type Point struct {
+	Row, Col int
+}
+
+// Offsets of one step in each compass direction, North is row 0
+var (
+	N  = Point{-1, 0}
+	NE = Point{-1, 1}
+	E  = Point{0, 1}
+	SE = Point{1, 1}
+	S  = Point{1, 0}
+	SW = Point{1, -1}
+	W  = Point{0, -1}
+	NW = Point{-1, -1}
+)
+
+// Dirs4 are the orthogonal directions, clockwise from North
+var Dirs4 = []Point{N, E, S, W}
+
+// Dirs8 are the orthogonal and diagonal directions, clockwise from North
+var Dirs8 = []Point{N, NE, E, SE, S, SW, W, NW}
+
+// Add returns p moved by q
+func (p Point) Add(q Point) Point {
+	return Point{p.Row + q.Row, p.Col + q.Col}
+}
+
+// Sub returns the offset from q to p
+func (p Point) Sub(q Point) Point {
+	return Point{p.Row - q.Row, p.Col - q.Col}
+}
+
+// Mul returns p scaled by k
+func (p Point) Mul(k int) Point {
+	return Point{p.Row * k, p.Col * k}
+}
+
+// TurnRight returns the direction a quarter turn clockwise from p
+func (p Point) TurnRight() Point {
+	return Point{p.Col, -p.Row}
+}
+
+// TurnLeft returns the direction a quarter turn counter-clockwise from p
+func (p Point) TurnLeft() Point {
+	return Point{-p.Col, p.Row}
+}
+
+// String formats p as "row,col"
+func (p Point) String() string {
+	return strconv.Itoa(p.Row) + "," + strconv.Itoa(p.Col)
+}
@@ -0,0 +1,300 @@
+// Package parse reads the shapes puzzle inputs come in: lines, stanzas
+// separated by blank lines, lists of numbers, key/value pairs and lines that
+// follow a template like "p=%d,%d v=%d,%d". Every piece keeps its position,
+// so a malformed input gives an *input.ParseError pointing at the offending
+// text.
+package parse
+
+import (
+	"adventcode2024/input"
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Line is one line of an input
+type Line struct {
+	Number int // 1-based
+	input.Field
+}
+
+// Error returns a ParseError pointing at the whole line
+func (l Line) Error(expected string) *input.ParseError {
+	pe := l.Field.Error(expected)
+	pe.Line = l.Number
+	return pe
+}
+
+// Blank reports whether the line holds nothing but white space
+func (l Line) Blank() bool {
+	return strings.TrimSpace(l.Text) == ""
+}
+
+// Lines reads every line of r, see Each
+func Lines(r io.Reader) ([]Line, error) {
+	var lines []Line
+	err := Each(r, func(l Line) error {
+		lines = append(lines, l)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// Each calls fn with every line of r, however long, without its line ending,
+// and stops at the first error fn returns. Blank lines at the end of the
+// input are never passed on, so an input of only blank lines has no lines.
+// Only the current line and the blank lines before it are held in memory.
+func Each(r io.Reader, fn func(l Line) error) error {
+	var blanks []Line // Blank lines held until a line that is not blank
+	br := bufio.NewReader(r)
+	for n := 1; ; n++ {
+		text, err := br.ReadString('\n')
+		if text != "" {
+			text = strings.TrimSuffix(strings.TrimSuffix(text, "\n"), "\r")
+			l := Line{Number: n, Field: input.Line(text)}
+			if l.Blank() {
+				blanks = append(blanks, l)
+			} else {
+				for _, blank := range blanks {
+					if err := fn(blank); err != nil {
+						return err
+					}
+				}
+				blanks = blanks[:0]
+				if err := fn(l); err != nil {
+					return err
+				}
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// EachStanza calls fn with every group of lines separated by one or more
+// blank lines, see Stanzas, holding only one group in memory
+func EachStanza(r io.Reader, fn func(stanza []Line) error) error {
+	var stanza []Line
+	err := Each(r, func(l Line) error {
+		if !l.Blank() {
+			stanza = append(stanza, l)
+			return nil
+		}
+		if len(stanza) == 0 {
+			return nil
+		}
+		err := fn(stanza)
+		stanza = nil
+		return err
+	})
+	if err != nil || len(stanza) == 0 {
+		return err
+	}
+	return fn(stanza)
+}
+
+// Join returns the text of lines joined without separators
+func Join(lines []Line) string {
+	var sb strings.Builder
+	for _, l := range lines {
+		sb.WriteString(l.Text)
+	}
+	return sb.String()
+}
+
+// Stanzas splits lines into groups separated by one or more blank lines
+func Stanzas(lines []Line) [][]Line {
+	var stanzas [][]Line
+	start := -1
+	for i, l := range lines {
+		switch {
+		case l.Blank() && start >= 0:
+			stanzas = append(stanzas, lines[start:i])
+			start = -1
+		case !l.Blank() && start < 0:
+			start = i
+		}
+	}
+	if start >= 0 {
+		stanzas = append(stanzas, lines[start:])
+	}
+	return stanzas
+}
+
+// List splits a field into the items of a list separated by sep, or by runs
+// of white space when sep is empty
+func List(f input.Field, sep string) []input.Field {
+	if sep == "" {
+		return f.Fields()
+	}
+	return f.Split(sep)
+}
+
+// IntList parses a list separated by sep, see List, as ints
+func IntList(f input.Field, sep string) ([]int, error) {
+	items := List(f, sep)
+	values := make([]int, len(items))
+	for i, item := range items {
+		n, err := item.Int()
+		if err != nil {
+			return nil, err
+		}
+		values[i] = n
+	}
+	return values, nil
+}
+
+// Int64List parses a list separated by sep, see List, as int64 values
+func Int64List(f input.Field, sep string) ([]int64, error) {
+	items := List(f, sep)
+	values := make([]int64, len(items))
+	for i, item := range items {
+		n, err := item.Int64()
+		if err != nil {
+			return nil, err
+		}
+		values[i] = n
+	}
+	return values, nil
+}
+
+// Ints returns every integer in a field, ignoring the text around them.
+// A '-' or '+' right before the digits is the sign of the number.
+func Ints(f input.Field) ([]int, error) {
+	var values []int
+	text := f.Text
+	for i := 0; i < len(text); i++ {
+		start := i
+		if (text[i] == '-' || text[i] == '+') && i+1 < len(text) && isDigit(text[i+1]) {
+			i++
+		}
+		if !isDigit(text[i]) {
+			continue
+		}
+		for i+1 < len(text) && isDigit(text[i+1]) {
+			i++
+		}
+		n, err := input.Field{Text: text[start : i+1], Column: f.Column + start}.Int()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, n)
+	}
+	return values, nil
+}
+
+// Cut splits a key/value pair like "190: 10 19" around the first sep.
+// found is false when the field has no sep.
+func Cut(f input.Field, sep string) (key, value input.Field, found bool) {
+	i := strings.Index(f.Text, sep)
+	if i < 0 {
+		return f, input.Field{Column: f.Column + len(f.Text)}, false
+	}
+	key = input.Field{Text: f.Text[:i], Column: f.Column}
+	value = input.Field{Text: f.Text[i+len(sep):], Column: f.Column + i + len(sep)}
+	return key, value, true
+}
+
+// Match matches a field against a template such as "p=%d,%d v=%d,%d" and
+// returns the text of each %d. Text between verbs must match exactly, and
+// %% stands for a percent sign. A %d takes everything up to the first byte
+// of the text that follows it in the template, or the rest of the field,
+// and must be an integer.
+func Match(f input.Field, template string) ([]input.Field, error) {
+	parts, verbs := splitTemplate(template)
+	expected := strconv.Quote(template)
+	fields := make([]input.Field, 0, verbs)
+	rest := f
+	for i, part := range parts {
+		if i%2 == 0 {
+			// Literal text
+			n := commonPrefix(rest.Text, part)
+			if n < len(part) {
+				return nil, input.Field{Text: rest.Text[n:], Column: rest.Column + n}.Error(expected)
+			}
+			rest, _ = rest.CutPrefix(part)
+			continue
+		}
+
+		// A %d, ended by the next literal text
+		end := len(rest.Text)
+		if next := parts[i+1]; next != "" {
+			if j := strings.IndexByte(rest.Text, next[0]); j >= 0 {
+				end = j
+			}
+		}
+		value := input.Field{Text: rest.Text[:end], Column: rest.Column}
+		if _, err := value.Int(); err != nil {
+			return nil, err
+		}
+		fields = append(fields, value)
+		rest = input.Field{Text: rest.Text[end:], Column: rest.Column + end}
+	}
+	if rest.Text != "" {
+		return nil, rest.Error(expected)
+	}
+	return fields, nil
+}
+
+// Scanf matches a field against a template like Match and stores the value of
+// each %d in the next of values
+func Scanf(f input.Field, template string, values ...*int) error {
+	fields, err := Match(f, template)
+	if err != nil {
+		return err
+	}
+	if len(fields) != len(values) {
+		return fmt.Errorf("parse: template %q has %d verbs for %d values", template, len(fields), len(values))
+	}
+	for i, field := range fields {
+		if *values[i], err = field.Int(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitTemplate splits a template into literal text and verbs, alternating
+// and starting and ending with literal text, which may be empty
+func splitTemplate(template string) (parts []string, verbs int) {
+	var literal strings.Builder
+	for i := 0; i < len(template); i++ {
+		switch {
+		case strings.HasPrefix(template[i:], "%%"):
+			literal.WriteByte('%')
+			i++
+		case strings.HasPrefix(template[i:], "%d"):
+			parts = append(parts, literal.String(), "%d")
+			literal.Reset()
+			verbs++
+			i++
+		default:
+			literal.WriteByte(template[i])
+		}
+	}
+	return append(parts, literal.String()), verbs
+}
+
+// commonPrefix returns the length of the longest common prefix of a and b
+func commonPrefix(a, b string) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
+
+// isDigit reports whether c is an ASCII digit
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
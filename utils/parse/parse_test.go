@@ -0,0 +1,194 @@
+package parse
+
+import (
+	"adventcode2024/input"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// position returns the line, column and text of a ParseError, or fails
+func position(t *testing.T, err error) (int, int, string) {
+	t.Helper()
+	var pe *input.ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("error = %v, want a *input.ParseError", err)
+	}
+	return pe.Line, pe.Column, pe.Text
+}
+
+func TestLines(t *testing.T) {
+	long := strings.Repeat("7", 200_000)
+	lines, err := Lines(strings.NewReader("ab\r\n" + long + "\n\ncd\n\n \n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want 4", len(lines))
+	}
+	if lines[0].Text != "ab" || lines[1].Text != long || lines[2].Text != "" || lines[3].Text != "cd" {
+		t.Errorf("lines %q %d %q %q", lines[0].Text, len(lines[1].Text), lines[2].Text, lines[3].Text)
+	}
+	if lines[3].Number != 4 || lines[3].Column != 1 {
+		t.Errorf("last line at %d:%d, want 4:1", lines[3].Number, lines[3].Column)
+	}
+	if pe := lines[3].Error("ef"); pe.Line != 4 || pe.Column != 1 || pe.Text != "cd" {
+		t.Errorf("Error() = %v, want 4:1 cd", pe)
+	}
+
+	lines, err = Lines(strings.NewReader("\n \n"))
+	if err != nil || len(lines) != 0 {
+		t.Errorf("blank input gave %d lines, %v", len(lines), err)
+	}
+}
+
+func TestStanzas(t *testing.T) {
+	lines, _ := Lines(strings.NewReader("\na\nb\n\n\nc\n \nd\n"))
+	var got []string
+	for _, stanza := range Stanzas(lines) {
+		got = append(got, Join(stanza)+"@"+string(rune('0'+stanza[0].Number)))
+	}
+	if want := []string{"ab@2", "c@6", "d@8"}; !slices.Equal(got, want) {
+		t.Errorf("stanzas %v, want %v", got, want)
+	}
+}
+
+func TestLists(t *testing.T) {
+	ints, err := IntList(input.Line("3   4\t-5"), "")
+	if err != nil || !slices.Equal(ints, []int{3, 4, -5}) {
+		t.Errorf("IntList = %v, %v", ints, err)
+	}
+	big, err := Int64List(input.Line("75,47,8589934592"), ",")
+	if err != nil || !slices.Equal(big, []int64{75, 47, 8589934592}) {
+		t.Errorf("Int64List = %v, %v", big, err)
+	}
+
+	_, err = IntList(input.Line("75,4x,61"), ",")
+	if line, col, text := position(t, err); line != 0 || col != 4 || text != "4x" {
+		t.Errorf("IntList error at %d:%d %q, want 0:4 4x", line, col, text)
+	}
+	_, err = IntList(input.Line("75,47,"), ",")
+	if _, col, text := position(t, err); col != 7 || text != "" {
+		t.Errorf("IntList error at %d %q, want 7 and nothing", col, text)
+	}
+}
+
+func TestInts(t *testing.T) {
+	ints, err := Ints(input.Line("Button A: X+94, Y-34 p=0,4 v=3,--3 x-"))
+	if want := []int{94, -34, 0, 4, 3, -3}; err != nil || !slices.Equal(ints, want) {
+		t.Errorf("Ints = %v, %v, want %v", ints, err, want)
+	}
+	_, err = Ints(input.Line("a 99999999999999999999"))
+	if _, col, _ := position(t, err); col != 3 {
+		t.Errorf("overflow reported at column %d, want 3", col)
+	}
+}
+
+func TestCut(t *testing.T) {
+	key, value, ok := Cut(input.Line("190: 10 19"), ": ")
+	if !ok || key != (input.Field{Text: "190", Column: 1}) || value != (input.Field{Text: "10 19", Column: 6}) {
+		t.Errorf("Cut = %v %v %v", key, value, ok)
+	}
+	if _, value, ok := Cut(input.Line("190 10"), ": "); ok || value.Column != 7 {
+		t.Errorf("Cut without sep = %v %v", value, ok)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	var px, py, vx, vy int
+	if err := Scanf(input.Line("p=0,4 v=3,-3"), "p=%d,%d v=%d,%d", &px, &py, &vx, &vy); err != nil {
+		t.Fatal(err)
+	}
+	if px != 0 || py != 4 || vx != 3 || vy != -3 {
+		t.Errorf("Scanf = %d %d %d %d", px, py, vx, vy)
+	}
+
+	fields, err := Match(input.Line("Button A: X+94, Y+34"), "Button A: X+%d, Y+%d")
+	if err != nil || len(fields) != 2 || fields[0].Column != 13 || fields[1].Column != 19 {
+		t.Errorf("Match = %v, %v", fields, err)
+	}
+	if _, err := Match(input.Line("100% of 7"), "%d%% of %d"); err != nil {
+		t.Errorf("Match with %%%% = %v", err)
+	}
+
+	tests := []struct {
+		text     string
+		column   int
+		found    string
+		template string
+	}{
+		{"p=6,3 v=-1,b", 12, "b", "p=%d,%d v=%d,%d"},
+		{"p=4x,3 v=1,1", 3, "4x", "p=%d,%d v=%d,%d"},
+		{"p=1,2", 6, "", "p=%d,%d v=%d,%d"},
+		{"Prize: X=12748, Z=12176", 17, "Z=12176", "Prize: X=%d, Y=%d"},
+		{"Prize: X=1, Y=2. more", 17, " more", "Prize: X=%d, Y=%d."},
+	}
+	for _, tt := range tests {
+		_, err := Match(input.Line(tt.text), tt.template)
+		if _, col, text := position(t, err); col != tt.column || text != tt.found {
+			t.Errorf("Match(%q) error at %d %q, want %d %q", tt.text, col, text, tt.column, tt.found)
+		}
+	}
+}
+
+func TestEach(t *testing.T) {
+	var got []int
+	stop := errors.New("stop")
+	err := Each(strings.NewReader("a\n\n \nb\nc\nd\n\n"), func(l Line) error {
+		got = append(got, l.Number)
+		if l.Text == "c" {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) || !slices.Equal(got, []int{1, 2, 3, 4, 5}) {
+		t.Errorf("Each visited %v and returned %v", got, err)
+	}
+
+	var stanzas []string
+	err = EachStanza(strings.NewReader("\na\nb\n\n\nc\n \nd\n\n"), func(stanza []Line) error {
+		stanzas = append(stanzas, Join(stanza))
+		return nil
+	})
+	if err != nil || !slices.Equal(stanzas, []string{"ab", "c", "d"}) {
+		t.Errorf("EachStanza gave %v, %v", stanzas, err)
+	}
+}
+
+// FuzzNumbers checks that the number parsers reject any line they cannot read
+// with a *input.ParseError instead of panicking
+func FuzzNumbers(f *testing.F) {
+	for _, line := range []string{"3   4", "7 6 4 2 1", "75,47,61,53,29", "p=0,4 v=3,-3", "99999999999999999999", ""} {
+		f.Add(line)
+	}
+	f.Fuzz(func(t *testing.T, line string) {
+		var pe *input.ParseError
+		for _, sep := range []string{"", ","} {
+			if _, err := IntList(input.Line(line), sep); err != nil && !errors.As(err, &pe) {
+				t.Errorf("IntList(%q, %q) returned %v, want a *input.ParseError", line, sep, err)
+			}
+			if _, err := Int64List(input.Line(line), sep); err != nil && !errors.As(err, &pe) {
+				t.Errorf("Int64List(%q, %q) returned %v, want a *input.ParseError", line, sep, err)
+			}
+		}
+		if _, err := Ints(input.Line(line)); err != nil && !errors.As(err, &pe) {
+			t.Errorf("Ints(%q) returned %v, want a *input.ParseError", line, err)
+		}
+	})
+}
+
+// FuzzMatch checks that matching any line against any template never panics
+// and gives one field per %d
+func FuzzMatch(f *testing.F) {
+	f.Add("p=0,4 v=3,-3", "p=%d,%d v=%d,%d")
+	f.Add("Button A: X+94, Y+34", "Button A: X+%d, Y+%d")
+	f.Add("Prize: X=8400, Y=5400", "Prize: X=%d, Y=%d")
+	f.Add("100% of 7", "%d%% of %d")
+	f.Fuzz(func(t *testing.T, line, template string) {
+		fields, err := Match(input.Line(line), template)
+		if _, verbs := splitTemplate(template); err == nil && len(fields) != verbs {
+			t.Errorf("Match(%q, %q) gave %d fields for %d verbs", line, template, len(fields), verbs)
+		}
+	})
+}
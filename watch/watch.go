@@ -0,0 +1,93 @@
+// Package watch polls files for changes. Polling needs nothing from the
+// operating system and is cheap for the handful of files of one day.
+package watch
+
+import (
+	"context"
+	"os"
+	"slices"
+	"time"
+)
+
+// Stamp is what a poll sees of a file, the zero Stamp for a missing file
+type Stamp struct {
+	ModTime time.Time
+	Size    int64
+}
+
+// equal reports whether two stamps are of the same file contents, as far as polling can tell
+func (s Stamp) equal(other Stamp) bool {
+	return s.ModTime.Equal(other.ModTime) && s.Size == other.Size
+}
+
+// Snapshot holds the stamps of the watched files by path
+type Snapshot map[string]Stamp
+
+// Take stamps every path
+func Take(paths []string) Snapshot {
+	s := make(Snapshot, len(paths))
+	for _, path := range paths {
+		var stamp Stamp
+		if info, err := os.Stat(path); err == nil {
+			stamp = Stamp{ModTime: info.ModTime(), Size: info.Size()}
+		}
+		s[path] = stamp
+	}
+	return s
+}
+
+// Changed returns the paths that were created, removed or modified between
+// s and next, sorted
+func (s Snapshot) Changed(next Snapshot) []string {
+	var changed []string
+	for path, stamp := range next {
+		if !s[path].equal(stamp) {
+			changed = append(changed, path)
+		}
+	}
+	for path, stamp := range s {
+		if _, ok := next[path]; !ok && !stamp.equal(Stamp{}) {
+			changed = append(changed, path)
+		}
+	}
+	slices.Sort(changed)
+	return changed
+}
+
+// Poller waits for changes to a set of files
+type Poller struct {
+	Paths    func() []string // Files to watch, listed again on every poll so new files are seen
+	Interval time.Duration   // Time between polls
+}
+
+// Wait polls until a file differs from since and returns the new snapshot and
+// the changed paths. Editors often write a file in several steps, so Wait
+// only returns once a poll sees no further change.
+func (p *Poller) Wait(ctx context.Context, since Snapshot) (Snapshot, []string, error) {
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+
+	current := since
+	settling := false
+	for {
+		select {
+		case <-ctx.Done():
+			return since, nil, ctx.Err()
+		case <-ticker.C:
+		}
+
+		next := Take(p.Paths())
+		if len(current.Changed(next)) > 0 {
+			settling = true
+			current = next
+			continue
+		}
+		if settling {
+			// A file changed back to how it was is no change
+			if changed := since.Changed(current); len(changed) > 0 {
+				return current, changed, nil
+			}
+			settling = false
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// TestChanged checks that created, removed and modified files are reported
+func TestChanged(t *testing.T) {
+	dir := t.TempDir()
+	kept, edited, removed, created := filepath.Join(dir, "kept"), filepath.Join(dir, "edited"), filepath.Join(dir, "removed"), filepath.Join(dir, "created")
+	for _, path := range []string{kept, edited, removed} {
+		if err := os.WriteFile(path, []byte("1"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	paths := []string{kept, edited, removed, created}
+	before := Take(paths)
+
+	if err := os.WriteFile(edited, []byte("12"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(removed); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(created, []byte("1"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{created, edited, removed}
+	if got := before.Changed(Take(paths)); !slices.Equal(got, want) {
+		t.Errorf("Changed = %v, want %v", got, want)
+	}
+	if got := before.Changed(Take(paths[:1])); !slices.Equal(got, []string{edited, removed}) {
+		t.Errorf("Changed after unwatching = %v, want the files that existed", got)
+	}
+	if got := before.Changed(before); len(got) != 0 {
+		t.Errorf("Changed of the same snapshot = %v", got)
+	}
+}
+
+// TestWait checks that Wait returns once a modified file settles, and stops with ctx
+func TestWait(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "input.txt")
+	if err := os.WriteFile(path, []byte("1"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p := &Poller{Paths: func() []string { return []string{path} }, Interval: 5 * time.Millisecond}
+	since := Take(p.Paths())
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		later := time.Now().Add(time.Hour)
+		os.Chtimes(path, later, later)
+	}()
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+	next, changed, err := p.Wait(ctx, since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(changed, []string{path}) {
+		t.Errorf("changed = %v, want %v", changed, []string{path})
+	}
+
+	ctx, cancel = context.WithTimeout(t.Context(), 30*time.Millisecond)
+	defer cancel()
+	if _, changed, err := p.Wait(ctx, next); !errors.Is(err, context.DeadlineExceeded) || changed != nil {
+		t.Errorf("Wait without changes = %v, %v, want the deadline", changed, err)
+	}
+}